@@ -1,5 +1,49 @@
 package cmd
 
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
 const (
-	ConfigFlag = "config"
+	ConfigFlag    = "config"
+	DirectionFlag = "direction"
+	OutputFlag    = "output"
+	ManifestFlag  = "manifest"
+	StateFlag     = "state"
+	DryRunFlag    = "dry-run"
+	GasPriceFlag  = "gas-price"
+	PipelineFlag  = "pipeline"
+	SummaryFlag   = "summary"
+	NetworkFlag   = "network"
+	ExtractorFlag = "extractor"
 )
+
+// NetworkFlags select the config and its network profile, shared by every subcommand that reads the config.
+var NetworkFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  ConfigFlag,
+		Value: DefaultConfig,
+	},
+	&cli.StringFlag{
+		Name:    NetworkFlag,
+		Usage:   "Network profile: ethereum, bsc, fantom, polygon or one from the config, added to the default state, manifest and summary file names",
+		EnvVars: []string{"DEPLOYER_NETWORK"},
+	},
+}
+
+// networkFile returns the file of the flag. The default file of a network profile gets its name,
+// so deployments to several networks do not overwrite each other.
+func networkFile(ctx *cli.Context, flag string) string {
+	filename := ctx.String(flag)
+	network := ctx.String(NetworkFlag)
+	if filename == "" || network == "" || ctx.IsSet(flag) {
+		return filename
+	}
+
+	ext := filepath.Ext(filename)
+
+	return strings.TrimSuffix(filename, ext) + "-" + network + ext
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	PredictAddressesCommand = &cli.Command{
+		Name:        "predict-addresses",
+		Usage:       "Predict CREATE2 addresses",
+		Description: "Prints the QueueLib, nebula and port addresses a CREATE2 deployment with the config will use",
+		Action:      predictAddresses,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  DirectionFlag,
+				Usage: "Port direction when the config has no port list: non-evm-based, evm-based or nebula",
+				Value: NonEvmBasedDirection,
+			},
+			&cli.StringFlag{
+				Name:  ExtractorFlag,
+				Usage: "Nebula data type: int64, string or bytes, the config extractor or bytes by default",
+			},
+		}, NetworkFlags...),
+	}
+)
+
+func create2Deployment(cfg *config.Create2Config) (*deployer.Create2, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	factory := cfg.Factory
+	if factory == "" {
+		factory = deployer.DefaultCreate2Factory
+	}
+
+	salt := crypto.Keccak256Hash([]byte(cfg.Salt))
+	if strings.HasPrefix(cfg.Salt, "0x") {
+		salt = common.BytesToHash(hexutil.MustDecode(cfg.Salt))
+	}
+
+	return &deployer.Create2{
+		Factory: common.HexToAddress(factory),
+		Salt:    salt,
+	}, nil
+}
+
+func predictAddresses(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if cfg.Create2 == nil {
+		return fmt.Errorf("create2 is not configured")
+	}
+
+	ports, err := portDeployments(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	create2, err := create2Deployment(cfg.Create2)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Factory address: %s\n", create2.Factory.Hex())
+
+	for _, port := range ports {
+		fmt.Printf("---------%v %s---------: \n", port.Kind(), port.Name)
+		fmt.Printf("QueueLib address: %s\n", create2.ForPort(port.Name).PredictQueueLibAddress().Hex())
+
+		if port.NebulaOnly {
+			nebulaAddress, err := create2.ForPort(port.Name).PredictNebulaAddress(
+				cfg.ExistingGravityAddress,
+				int(port.DataType),
+				port.Oracles,
+				port.BftCoefficient,
+			)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Nebula address: %s\n", nebulaAddress.Hex())
+			continue
+		}
+
+		if port.TokenAddress == "" {
+			fmt.Println("Existing token address is empty, a test token address cannot be predicted")
+			continue
+		}
+
+		nebulaAddress, portAddress, err := create2.ForPort(port.Name).PredictPortAddresses(
+			cfg.ExistingGravityAddress,
+			int(port.DataType),
+			port.TokenAddress,
+			port.Oracles,
+			port.BftCoefficient,
+			port.PortType,
+		)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Port address: %s\n", portAddress.Hex())
+		fmt.Printf("Nebula address: %s\n", nebulaAddress.Hex())
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	WithGravityFlag = "with-gravity"
+)
+
+// dryRun runs the deployment on a simulated chain with a throwaway key
+// and prints the gas used by every step.
+func dryRun(ctx *cli.Context, cfg *config.EthereumConfig) error {
+	fmt.Println("Dry run on a simulated chain")
+
+	gasPrice, err := dryRunGasPrice(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	transactor, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(deployer.SimulatedChainID))
+	if err != nil {
+		return err
+	}
+
+	backend := deployer.NewSimulatedBackend(transactor.From)
+	defer backend.Close()
+
+	policy, err := gasPolicy(cfg.GasPolicy)
+	if err != nil {
+		return err
+	}
+
+	create2, err := create2Deployment(cfg.Create2)
+	if err != nil {
+		return err
+	}
+
+	ethDeployer := deployer.NewEthDeployer(backend, transactor)
+	ethDeployer.SetGasPolicy(policy)
+	ethDeployer.SetCreate2(create2)
+
+	if ctx.Bool(WithGravityFlag) {
+		err = cfg.ValidateConsuls()
+		if err != nil {
+			return err
+		}
+
+		cfg.ExistingGravityAddress, err = ethDeployer.DeployGravity(cfg.ConsulsAddress, cfg.GravityBftCoefficient, ctx.Context)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = cfg.ValidateContracts(ctx.String(DirectionFlag))
+	if err != nil {
+		return err
+	}
+
+	ports, err := portDeployments(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	_, err = deployPorts(ctx, cfg, ethDeployer, ports, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("---------Gas usage---------")
+
+	var recordedGas uint64
+	for _, contract := range ethDeployer.Manifest(big.NewInt(deployer.SimulatedChainID)).Contracts {
+		for _, tx := range contract.Transactions {
+			fmt.Printf("%s %s: %d\n", contract.Name, tx.Step, tx.GasUsed)
+			recordedGas += tx.GasUsed
+		}
+	}
+
+	// the total covers every sent transaction, also those without a manifest record
+	totalGas := ethDeployer.GasUsed()
+	if totalGas > recordedGas {
+		fmt.Printf("Other transactions: %d\n", totalGas-recordedGas)
+	}
+
+	cost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(totalGas))
+
+	fmt.Printf("Total gas: %d\n", totalGas)
+	fmt.Printf("Gas price: %s gwei\n", deployer.FormatAmount(gasPrice, 9))
+	fmt.Printf("Total cost: %s%s\n", deployer.FormatAmount(cost, 18), currencySuffix(cfg.NativeCurrency))
+
+	return nil
+}
+
+func dryRunGasPrice(ctx *cli.Context, cfg *config.EthereumConfig) (*big.Int, error) {
+	if ctx.IsSet(GasPriceFlag) {
+		if ctx.Float64(GasPriceFlag) <= 0 {
+			return nil, fmt.Errorf("gas price must be positive: %v", ctx.Float64(GasPriceFlag))
+		}
+		gasPrice, _ := new(big.Float).Mul(big.NewFloat(ctx.Float64(GasPriceFlag)), big.NewFloat(params.GWei)).Int(nil)
+		return gasPrice, nil
+	}
+	if cfg.GasPolicy != nil && cfg.GasPolicy.MaxFeePerGas != "" {
+		return deployer.ParseAmount(cfg.GasPolicy.MaxFeePerGas, 9)
+	}
+
+	if cfg.NodeUrl == "" {
+		return nil, fmt.Errorf("node url is empty, use --%s to set the gas price", GasPriceFlag)
+	}
+
+	ethClient, err := ethclient.DialContext(ctx.Context, cfg.NodeUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	return ethClient.SuggestGasPrice(ctx.Context)
+}
+
+func currencySuffix(currency string) string {
+	if currency == "" {
+		return ""
+	}
+
+	return " " + currency
+}
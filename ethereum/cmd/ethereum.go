@@ -2,13 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
+	"math/big"
+
 	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
 	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"os"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/urfave/cli/v2"
@@ -18,11 +19,15 @@ type Network string
 type ChainId string
 
 const (
-	DefaultConfig = "ethereum-cfg.json"
+	DefaultConfig   = "ethereum-cfg.json"
+	DefaultManifest = "ethereum-manifest.json"
+	// DefaultGravityManifest keeps deploy-gravity from overwriting the manifest of the ports
+	DefaultGravityManifest = "ethereum-gravity-manifest.json"
+	DefaultState           = "ethereum-state.json"
 )
 
 const (
-	EvmBasedDirection = "evm-based"
+	EvmBasedDirection    = "evm-based"
 	NonEvmBasedDirection = "non-evm-based"
 )
 
@@ -37,88 +42,414 @@ var (
 				Usage:       "Deploy contracts",
 				Description: "",
 				Action:      deploy,
-				Flags: []cli.Flag{
+				Flags: append(append([]cli.Flag{
 					&cli.StringFlag{
-						Name:  ConfigFlag,
-						Value: DefaultConfig,
+						Name:  DirectionFlag,
+						Usage: "Port direction when the config has no port list: non-evm-based, evm-based or nebula",
+						Value: NonEvmBasedDirection,
 					},
 					&cli.StringFlag{
-						Name:  "direction",
-						Value: NonEvmBasedDirection,
+						Name:  ExtractorFlag,
+						Usage: "Nebula data type: int64, string or bytes, the config extractor or bytes by default",
+					},
+					&cli.StringFlag{
+						Name:  StateFlag,
+						Usage: "File with completed deployment steps, a rerun continues from the first missing step",
+						Value: DefaultState,
+					},
+					&cli.StringFlag{
+						Name:  ManifestFlag,
+						Usage: "File to write the deployment manifest to",
+						Value: DefaultManifest,
+					},
+					&cli.StringFlag{
+						Name:  SummaryFlag,
+						Usage: "File to write the combined summary of the deployed ports to",
+						Value: DefaultSummary,
+					},
+					&cli.BoolFlag{
+						Name:  PipelineFlag,
+						Usage: "Send the nebula, port and subscribe transactions without waiting for each to be mined",
+					},
+					&cli.BoolFlag{
+						Name:  PublishSourcesFlag,
+						Usage: "Verify the contract sources on the block explorer after the deployment",
 					},
-				},
+					&cli.BoolFlag{
+						Name:  DryRunFlag,
+						Usage: "Run the deployment on an in-memory simulated chain and print gas costs",
+					},
+					&cli.BoolFlag{
+						Name:  WithGravityFlag,
+						Usage: "Deploy a gravity contract first in the dry run",
+					},
+					&cli.Float64Flag{
+						Name:  GasPriceFlag,
+						Usage: "Gas price in gwei for the dry run cost, MaxFeePerGas or the node suggestion by default",
+					},
+				}, NetworkFlags...), KeyFlags...),
+			},
+			{
+				Name:        "deploy-gravity",
+				Usage:       "Deploy gravity contract",
+				Description: "Deploys the gravity contract with the consuls and bft coefficient from the config",
+				Action:      deployGravity,
+				Flags: append(append([]cli.Flag{
+					&cli.StringFlag{
+						Name:  OutputFlag,
+						Usage: "File to save the gravity address to",
+					},
+					&cli.StringFlag{
+						Name:  ManifestFlag,
+						Usage: "File to write the deployment manifest to",
+						Value: DefaultGravityManifest,
+					},
+				}, NetworkFlags...), KeyFlags...),
 			},
+			FaucetCommand,
+			PredictAddressesCommand,
+			VerifyCommand,
+			PublishSourcesCommand,
+			NebulaCommand,
+			GravityCommand,
+			PlanCommand,
+			SignCommand,
+			BroadcastCommand,
+			ProposeCommand,
+			TransferOwnershipCommand,
 		},
 	}
 )
 
-func deploy(ctx *cli.Context) error {
-	cfgPath := ctx.String(ConfigFlag)
-	cfgDirection := ctx.String("direction")
+func directionPortType(direction string) (deployer.PortType, error) {
+	switch direction {
+	case NonEvmBasedDirection:
+		return deployer.IBPort, nil
+	case EvmBasedDirection:
+		return deployer.LUPort, nil
+	}
 
+	return 0, fmt.Errorf("unknown direction: %s", direction)
+}
+
+func loadConfig(ctx *cli.Context) (*config.EthereumConfig, error) {
 	cfg := new(config.EthereumConfig)
-	config.ParseConfig(cfgPath, cfg)
-	err := cfg.Validate()
+	err := config.ParseConfig(ctx.String(ConfigFlag), cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Println("Deploy ethereum contracts")
+	network := ctx.String(NetworkFlag)
+	if network != "" {
+		err = cfg.ApplyNetwork(network)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Printf("Network: %s\n", network)
+	}
+
+	return cfg, nil
+}
+
+// dialNode connects to the node and checks it serves the configured chain.
+func dialNode(ctx *cli.Context, cfg *config.EthereumConfig) (*ethclient.Client, error) {
 	fmt.Printf("Node url: %s\n", cfg.NodeUrl)
 
 	ethClient, err := ethclient.DialContext(ctx.Context, cfg.NodeUrl)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	chainID := big.NewInt(cfg.ChainID)
+	nodeChainID, err := ethClient.ChainID(ctx.Context)
+	if err != nil {
+		return nil, err
 	}
+	if nodeChainID.Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("node chain id %s does not match config chain id %s", nodeChainID, chainID)
+	}
+
+	fmt.Printf("Chain id: %s\n", chainID)
 
-	privKey := os.Getenv("DEPLOYER_PRIV_KEY")
+	return ethClient, nil
+}
 
-	privateKey, err := crypto.HexToECDSA(privKey)
+// checkGravity checks the configured gravity contract is deployed on the chain of the node.
+func checkGravity(ctx *cli.Context, cfg *config.EthereumConfig, ethClient deployer.Backend) error {
+	code, err := ethClient.CodeAt(ctx.Context, common.HexToAddress(cfg.ExistingGravityAddress), nil)
 	if err != nil {
 		return err
 	}
+	if len(code) == 0 {
+		return fmt.Errorf("gravity %s is not deployed on chain %d", cfg.ExistingGravityAddress, cfg.ChainID)
+	}
+
+	return nil
+}
+
+func newEthDeployer(ctx *cli.Context, cfg *config.EthereumConfig) (*deployer.EthDeployer, error) {
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return keyedDeployer(ctx, cfg, ethClient)
+}
+
+// keyedDeployer creates a deployer that signs with the deployer key on a connected node.
+func keyedDeployer(ctx *cli.Context, cfg *config.EthereumConfig, ethClient *ethclient.Client) (*deployer.EthDeployer, error) {
+	chainID := big.NewInt(cfg.ChainID)
+	privateKey, err := loadPrivateKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transactor, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := gasPolicy(cfg.GasPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	create2, err := create2Deployment(cfg.Create2)
+	if err != nil {
+		return nil, err
+	}
 
-	transactor := bind.NewKeyedTransactor(privateKey)
 	ethDeployer := deployer.NewEthDeployer(ethClient, transactor)
+	ethDeployer.SetGasPolicy(policy)
+	ethDeployer.SetCreate2(create2)
 
-	fmt.Println("Deploy gravity contract")
+	return ethDeployer, nil
+}
+
+// saveManifest writes the manifest even when the deployment fails part way.
+func saveManifest(ctx *cli.Context, cfg *config.EthereumConfig, ethDeployer *deployer.EthDeployer) {
+	path := networkFile(ctx, ManifestFlag)
+	if path == "" {
+		return
+	}
+
+	err := ethDeployer.Manifest(big.NewInt(cfg.ChainID)).Save(path)
+	if err != nil {
+		fmt.Printf("Failed to save manifest: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Manifest: %s\n", path)
+}
+
+func deploy(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(DryRunFlag) {
+		return dryRun(ctx, cfg)
+	}
+
+	err = cfg.Validate(ctx.String(DirectionFlag))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Deploy ethereum contracts")
 
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	err = checkGravity(ctx, cfg, ethClient)
+	if err != nil {
+		return err
+	}
+
+	ethDeployer, err := keyedDeployer(ctx, cfg, ethClient)
+	if err != nil {
+		return err
+	}
+	defer saveManifest(ctx, cfg, ethDeployer)
+
+	deployState, err := deployer.LoadDeployState(networkFile(ctx, StateFlag))
+	if err != nil {
+		return err
+	}
+	err = deployState.CheckChain(big.NewInt(cfg.ChainID))
+	if err != nil {
+		return err
+	}
+
+	ports, err := portDeployments(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	summaries, err := deployPorts(ctx, cfg, ethDeployer, ports, deployState)
+	saveSummary(ctx, summaries)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(PublishSourcesFlag) {
+		return publishManifestSources(ctx, cfg, ethDeployer.Manifest(big.NewInt(cfg.ChainID)))
+	}
+
+	return nil
+}
+
+func deployPort(ctx *cli.Context, cfg *config.EthereumConfig, ethDeployer *deployer.EthDeployer, port *portDeployment, portState *deployer.PortState) (*deployer.GatewayPort, error) {
 	gravityAddress := cfg.ExistingGravityAddress
 
 	fmt.Printf("Gravity address: %s\n", gravityAddress)
 
-	var portType deployer.PortType
-	switch cfgDirection {
-	case NonEvmBasedDirection:
-		portType = deployer.IBPort
-	case EvmBasedDirection:
-		portType = deployer.LUPort
+	ethDeployer.SetCreate2Port(port.Name)
+
+	if port.NebulaOnly {
+		return deployNebula(ctx, cfg, ethDeployer, port, portState)
+	}
+
+	var err error
+	tokenAddress := port.TokenAddress
+	if tokenAddress == "" {
+		fmt.Printf("Deploy test token %s (%s)\n", port.TestToken.Name, port.TestToken.Symbol)
+
+		tokenAddress, err = ethDeployer.DeployTestToken(
+			port.TestToken.Name,
+			port.TestToken.Symbol,
+			port.TestToken.Decimals,
+			port.TestToken.InitialSupply,
+			portState,
+			ctx.Context,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Printf("Test token address: %s\n", tokenAddress)
 	}
 
-	var consulsList []common.Address
-	for _, consul := range cfg.ConsulsAddress {
-		consulsList = append(consulsList, common.HexToAddress(consul))
+	deployFn := ethDeployer.DeployPort
+	if ctx.Bool(PipelineFlag) {
+		deployFn = ethDeployer.DeployPortPipelined
 	}
 
-	port, err := ethDeployer.DeployPort(
+	gatewayPort, err := deployFn(
 		gravityAddress,
-		int(deployer.BytesType),
-		cfg.ExistingTokenAddress,
-		consulsList,
-		cfg.GravityBftCoefficient,
-		portType,
+		int(port.DataType),
+		tokenAddress,
+		port.Oracles,
+		port.BftCoefficient,
+		port.PortType,
+		port.Subscription,
+		portState,
+		ctx.Context,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("---------%v---------: \n", port.PortType.Format())
+	fmt.Printf("Port address: %s\n", gatewayPort.PortAddress)
+	fmt.Printf("Nebula address: %s\n", gatewayPort.NebulaAddress)
+	fmt.Printf("Token address: %s\n", gatewayPort.ERC20Address)
+
+	if port.PortType == deployer.IBPort {
+		fmt.Println("Grant the port mint and burn rights on the token")
+
+		// A test token deployed by this run is made for the port
+		transferOwnership := cfg.TransferTokenOwnership || port.TokenAddress == ""
+		err = ethDeployer.GrantPortRights(common.HexToAddress(gatewayPort.PortAddress), common.HexToAddress(gatewayPort.ERC20Address),
+			transferOwnership, ctx.Context)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.FinalOwner == "" {
+		return gatewayPort, nil
+	}
+
+	fmt.Printf("Transfer ownership to %s\n", cfg.FinalOwner)
+
+	contracts := []common.Address{
+		common.HexToAddress(gatewayPort.NebulaAddress),
+		common.HexToAddress(gatewayPort.PortAddress),
+	}
+	// The IB port mints and burns the token, so its ownership is not handed over
+	if port.TokenAddress == "" && port.PortType == deployer.LUPort {
+		contracts = append(contracts, common.HexToAddress(gatewayPort.ERC20Address))
+	}
+
+	err = ethDeployer.TransferOwnership(contracts, common.HexToAddress(cfg.FinalOwner), ctx.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	return gatewayPort, nil
+}
+
+// deployNebula deploys a nebula without a port, the subscribers are attached separately.
+func deployNebula(ctx *cli.Context, cfg *config.EthereumConfig, ethDeployer *deployer.EthDeployer, port *portDeployment, portState *deployer.PortState) (*deployer.GatewayPort, error) {
+	nebulaAddress, err := ethDeployer.DeployNebula(
+		cfg.ExistingGravityAddress,
+		int(port.DataType),
+		port.Oracles,
+		port.BftCoefficient,
+		portState,
 		ctx.Context,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Nebula address: %s\n", nebulaAddress)
+
+	if cfg.FinalOwner != "" {
+		fmt.Printf("Transfer ownership to %s\n", cfg.FinalOwner)
+
+		err = ethDeployer.TransferOwnership([]common.Address{common.HexToAddress(nebulaAddress)}, common.HexToAddress(cfg.FinalOwner), ctx.Context)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &deployer.GatewayPort{NebulaAddress: nebulaAddress}, nil
+}
+
+func deployGravity(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.ValidateGravity()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Deploy gravity contract")
+
+	ethDeployer, err := newEthDeployer(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer saveManifest(ctx, cfg, ethDeployer)
+
+	gravityAddress, err := ethDeployer.DeployGravity(cfg.ConsulsAddress, cfg.GravityBftCoefficient, ctx.Context)
 	if err != nil {
 		return err
 	}
 
 	fmt.Printf("Gravity address: %s\n", gravityAddress)
 
-	fmt.Printf("---------%v---------: \n", portType.Format())
-	fmt.Printf("Port address: %s\n", port.PortAddress)
-	fmt.Printf("Nebula address: %s\n", port.NebulaAddress)
-	fmt.Printf("Token address: %s\n", port.ERC20Address)
+	output := ctx.String(OutputFlag)
+	if output == "" {
+		return nil
+	}
 
-	return nil
+	return ioutil.WriteFile(output, []byte(gravityAddress), 0644)
 }
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/explorer"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	PublishSourcesFlag = "publish-sources"
+)
+
+var (
+	PublishSourcesCommand = &cli.Command{
+		Name:        "publish-sources",
+		Usage:       "Verify contract sources on the block explorer",
+		Description: "Submits the sources of the contracts in the manifest to an Etherscan compatible API and waits for the verification",
+		Action:      publishSources,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  ManifestFlag,
+				Value: DefaultManifest,
+			},
+		}, NetworkFlags...),
+	}
+)
+
+func publishSources(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := deployer.LoadManifest(networkFile(ctx, ManifestFlag))
+	if err != nil {
+		return err
+	}
+
+	return publishManifestSources(ctx, cfg, manifest)
+}
+
+// publishManifestSources verifies every manifest contract that has a source in the explorer config.
+func publishManifestSources(ctx *cli.Context, cfg *config.EthereumConfig, manifest *deployer.Manifest) error {
+	if cfg.Explorer == nil {
+		return fmt.Errorf("explorer is not configured")
+	}
+	err := cfg.Explorer.Validate()
+	if err != nil {
+		return err
+	}
+
+	client := explorer.NewClient(cfg.Explorer.ApiUrl, cfg.Explorer.ApiKey)
+
+	var failed int
+	for _, contract := range manifest.Contracts {
+		source, ok := cfg.Explorer.Contracts[contract.Name]
+		if !ok {
+			fmt.Printf("Skip %s %s: no source configured\n", contract.Name, contract.Address)
+			continue
+		}
+
+		sourceCode, err := ioutil.ReadFile(source.SourceFile)
+		if err != nil {
+			return err
+		}
+
+		contractName := source.ContractName
+		if contractName == "" {
+			contractName = contract.Name
+		}
+
+		fmt.Printf("Verify %s %s\n", contract.Name, contract.Address)
+
+		err = client.Verify(ctx.Context, &explorer.VerifyRequest{
+			ContractAddress:      contract.Address,
+			SourceCode:           string(sourceCode),
+			CodeFormat:           source.CodeFormat,
+			ContractName:         contractName,
+			CompilerVersion:      source.CompilerVersion,
+			OptimizationUsed:     source.OptimizationUsed,
+			Runs:                 source.Runs,
+			ConstructorArguments: contract.ConstructorData,
+			EVMVersion:           source.EVMVersion,
+			LicenseType:          source.LicenseType,
+		})
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", contract.Name, contract.Address, err)
+			continue
+		}
+
+		fmt.Printf("%s %s: verified\n", contract.Name, contract.Address)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d contracts failed verification", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	TokenFlag      = "token"
+	ToFlag         = "to"
+	AmountFlag     = "amount"
+	RecipientsFlag = "recipients"
+)
+
+var (
+	FaucetCommand = &cli.Command{
+		Name:        "faucet",
+		Usage:       "Mint test tokens",
+		Description: "Mints test tokens to a single recipient or to a CSV/JSON list of recipients",
+		Action:      faucet,
+		Flags: append(append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  TokenFlag,
+				Usage: "Token address, ExistingTokenAddress from the config by default",
+			},
+			&cli.StringFlag{
+				Name:  ToFlag,
+				Usage: "Recipient address",
+			},
+			&cli.StringFlag{
+				Name:  AmountFlag,
+				Usage: "Decimal amount of tokens, used for recipients without an amount",
+			},
+			&cli.StringFlag{
+				Name:  RecipientsFlag,
+				Usage: "CSV (address,amount) or JSON file with recipients",
+			},
+		}, NetworkFlags...), KeyFlags...),
+	}
+)
+
+type FaucetRecipient struct {
+	Address string
+	Amount  string
+}
+
+func faucet(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.ValidateNode()
+	if err != nil {
+		return err
+	}
+
+	token := ctx.String(TokenFlag)
+	if token == "" {
+		token = cfg.ExistingTokenAddress
+	}
+	if !common.IsHexAddress(token) {
+		return fmt.Errorf("invalid token address: %q", token)
+	}
+
+	recipients, err := faucetRecipients(ctx)
+	if err != nil {
+		return err
+	}
+
+	ethDeployer, err := newEthDeployer(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Token address: %s\n", token)
+
+	var failed int
+	for _, recipient := range recipients {
+		txHash, err := ethDeployer.Faucet(token, recipient.Address, recipient.Amount, ctx.Context)
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", recipient.Address, recipient.Amount, err)
+			continue
+		}
+
+		fmt.Printf("%s %s: %s\n", recipient.Address, recipient.Amount, txHash)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d faucet transactions failed", failed, len(recipients))
+	}
+
+	return nil
+}
+
+func faucetRecipients(ctx *cli.Context) ([]FaucetRecipient, error) {
+	amount := ctx.String(AmountFlag)
+
+	var recipients []FaucetRecipient
+	if to := ctx.String(ToFlag); to != "" {
+		recipients = append(recipients, FaucetRecipient{Address: to, Amount: amount})
+	}
+
+	if path := ctx.String(RecipientsFlag); path != "" {
+		list, err := readRecipients(path)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, list...)
+	}
+
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no recipients, use --%s or --%s", ToFlag, RecipientsFlag)
+	}
+
+	for i := range recipients {
+		if !common.IsHexAddress(recipients[i].Address) {
+			return nil, fmt.Errorf("invalid recipient address: %s", recipients[i].Address)
+		}
+		if recipients[i].Amount == "" {
+			recipients[i].Amount = amount
+		}
+		if recipients[i].Amount == "" {
+			return nil, fmt.Errorf("no amount for recipient %s", recipients[i].Address)
+		}
+	}
+
+	return recipients, nil
+}
+
+func readRecipients(path string) ([]FaucetRecipient, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var recipients []FaucetRecipient
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = json.NewDecoder(file).Decode(&recipients)
+		if err != nil {
+			return nil, err
+		}
+
+		return recipients, nil
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		recipient := FaucetRecipient{Address: strings.TrimSpace(record[0])}
+		if recipient.Address == "" {
+			continue
+		}
+		// a header row such as address,amount
+		if first && !common.IsHexAddress(recipient.Address) {
+			continue
+		}
+		if len(record) > 1 {
+			recipient.Amount = strings.TrimSpace(record[1])
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
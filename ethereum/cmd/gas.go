@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+)
+
+// gasPolicy converts the gwei and native currency amounts of the config into wei.
+func gasPolicy(cfg *config.GasPolicyConfig) (*deployer.GasPolicy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &deployer.GasPolicy{
+		Legacy:     cfg.TxType == config.LegacyTxType,
+		Multiplier: cfg.GasPriceMultiplier,
+		GasLimits:  cfg.GasLimits,
+	}
+
+	if cfg.MaxFeePerGas != "" {
+		policy.MaxFeePerGas, err = deployer.ParseAmount(cfg.MaxFeePerGas, 9)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.MaxPriorityFeePerGas != "" {
+		policy.MaxPriorityFeePerGas, err = deployer.ParseAmount(cfg.MaxPriorityFeePerGas, 9)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.FeeCeiling != "" {
+		policy.FeeCeiling, err = deployer.ParseAmount(cfg.FeeCeiling, 18)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	GravityFlag = "gravity"
+	ConsulsFlag = "consuls"
+)
+
+var (
+	gravityFlags = append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  GravityFlag,
+			Usage: "Gravity address, ExistingGravityAddress from the config by default",
+		},
+	}, NetworkFlags...)
+
+	GravityCommand = &cli.Command{
+		Name:  "gravity",
+		Usage: "Manage gravity consuls",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "info",
+				Usage:       "Show the current round and consuls",
+				Description: "Prints the round, bft coefficient and consuls read from the gravity contract",
+				Action:      gravityInfo,
+				Flags:       gravityFlags,
+			},
+			{
+				Name:  "update-consuls",
+				Usage: "Move gravity to new consuls",
+				Description: "Prints the message hash the current consuls sign for the new consuls and round, which must be after the current round, " +
+					"checks the collected signatures against the current consuls and submits the update once the quorum is reached",
+				Action: updateConsuls,
+				Flags: append(append(append(append([]cli.Flag{
+					&cli.StringSliceFlag{
+						Name:     ConsulsFlag,
+						Usage:    "New consul addresses, comma separated",
+						Required: true,
+					},
+				}, signatureFlags...), gravityFlags...), KeyFlags...), safeFlags...),
+			},
+		},
+	}
+)
+
+func gravityContractAddress(ctx *cli.Context, cfg *config.EthereumConfig) (common.Address, error) {
+	address := ctx.String(GravityFlag)
+	if address == "" {
+		address = cfg.ExistingGravityAddress
+	}
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("invalid gravity address %q, use --%s", address, GravityFlag)
+	}
+
+	return common.HexToAddress(address), nil
+}
+
+func gravityInfo(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.ValidateNode()
+	if err != nil {
+		return err
+	}
+
+	address, err := gravityContractAddress(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	info, err := deployer.ReadGravity(ethClient, address)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Gravity address: %s\n", address.Hex())
+	fmt.Printf("Round: %s\n", info.Round)
+	fmt.Printf("Bft coefficient: %s\n", info.BftCoefficient)
+	fmt.Printf("Consuls: %d\n", len(info.Consuls))
+	for _, consul := range info.Consuls {
+		fmt.Println(consul.Hex())
+	}
+
+	return nil
+}
+
+func updateConsuls(ctx *cli.Context) error {
+	consuls, err := addressesFlag(ctx, ConsulsFlag)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Consuls: %v\n", ctx.StringSlice(ConsulsFlag))
+
+	var update *deployer.ConsulsUpdate
+	messageHash := func(round *big.Int) common.Hash {
+		return deployer.UpdateMessageHash(consuls, round)
+	}
+
+	return submitSignedUpdate(ctx, "Gravity consuls update", messageHash,
+		func(cfg *config.EthereumConfig, ethClient *ethclient.Client, round *big.Int, signatures [][]byte) (deployer.SignedUpdate, error) {
+			address, err := gravityContractAddress(ctx, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			update, err = deployer.NewConsulsUpdate(ethClient, address, consuls, round, signatures)
+			return update, err
+		},
+		func(ethDeployer *deployer.EthDeployer) error {
+			txHash, err := ethDeployer.UpdateConsuls(update, ctx.Context)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Consuls updated: %s\n", txHash)
+
+			return nil
+		})
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	KeystoreFlag     = "keystore"
+	PasswordFileFlag = "password-file"
+
+	PrivKeyEnv = "DEPLOYER_PRIV_KEY"
+)
+
+var (
+	// KeyFlags select the deployer key, shared by every subcommand that sends transactions.
+	// Without a keystore the hex key from DEPLOYER_PRIV_KEY is used.
+	KeyFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    KeystoreFlag,
+			Usage:   "Encrypted V3 keystore file with the deployer key",
+			EnvVars: []string{"DEPLOYER_KEYSTORE"},
+		},
+		&cli.StringFlag{
+			Name:    PasswordFileFlag,
+			Usage:   "File with the keystore password, prompted for when not set",
+			EnvVars: []string{"DEPLOYER_PASSWORD_FILE"},
+		},
+	}
+)
+
+func loadPrivateKey(ctx *cli.Context) (*ecdsa.PrivateKey, error) {
+	keystorePath := ctx.String(KeystoreFlag)
+	if keystorePath == "" {
+		privKey := os.Getenv(PrivKeyEnv)
+		if privKey == "" {
+			return nil, fmt.Errorf("deployer key is not set, use --%s or %s", KeystoreFlag, PrivKeyEnv)
+		}
+
+		return crypto.HexToECDSA(strings.TrimPrefix(privKey, "0x"))
+	}
+
+	keyJson, err := ioutil.ReadFile(keystorePath)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := keystorePassword(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(keyJson, password)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt keystore %s: %w", keystorePath, err)
+	}
+
+	return key.PrivateKey, nil
+}
+
+func keystorePassword(ctx *cli.Context) (string, error) {
+	passwordFile := ctx.String(PasswordFileFlag)
+	if passwordFile == "" {
+		return prompt.Stdin.PromptPassword("Keystore password: ")
+	}
+
+	password, err := ioutil.ReadFile(passwordFile)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(password), "\r\n"), nil
+}
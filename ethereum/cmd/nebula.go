@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	OraclesFlag          = "oracles"
+	ContractFlag         = "contract"
+	MinConfirmationsFlag = "min-confirmations"
+	RewardFlag           = "reward"
+)
+
+var (
+	nebulaFlags = append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     NebulaFlag,
+			Usage:    "Nebula address",
+			Required: true,
+		},
+	}, NetworkFlags...)
+
+	NebulaCommand = &cli.Command{
+		Name:  "nebula",
+		Usage: "Manage nebula subscriptions and oracles",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "subscribe",
+				Usage: "Subscribe a contract to a nebula",
+				Description: "Subscribes the contract and prints the subscription id read from the nebula. " +
+					"The nebula has no unsubscribe, so a contract already subscribed with other parameters is refused",
+				Action: subscribe,
+				Flags: append(append(append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     ContractFlag,
+						Usage:    "Subscriber contract address",
+						Required: true,
+					},
+					&cli.UintFlag{
+						Name:  MinConfirmationsFlag,
+						Usage: "Minimum confirmations of the subscription",
+						Value: 1,
+					},
+					&cli.StringFlag{
+						Name:  RewardFlag,
+						Usage: "Reward of the subscription in wei",
+						Value: "0",
+					},
+				}, nebulaFlags...), KeyFlags...), safeFlags...),
+			},
+			{
+				Name:        "list",
+				Usage:       "List nebula subscriptions",
+				Description: "Prints every subscription of the nebula",
+				Action:      listSubscriptions,
+				Flags:       nebulaFlags,
+			},
+			{
+				Name:  "update-oracles",
+				Usage: "Move a nebula to new oracles",
+				Description: "Prints the message hash the consuls sign for the new oracles, " +
+					"checks the collected signatures against the gravity consuls and submits the update with a round not used by the nebula yet once the quorum is reached",
+				Action: updateOracles,
+				Flags: append(append(append(append([]cli.Flag{
+					&cli.StringSliceFlag{
+						Name:     OraclesFlag,
+						Usage:    "New oracle addresses, comma separated",
+						Required: true,
+					},
+				}, signatureFlags...), nebulaFlags...), KeyFlags...), safeFlags...),
+			},
+		},
+	}
+)
+
+func subscribe(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.ValidateNode()
+	if err != nil {
+		return err
+	}
+
+	minConfirmations := ctx.Uint(MinConfirmationsFlag)
+	if minConfirmations == 0 || minConfirmations > 255 {
+		return fmt.Errorf("min confirmations must be between 1 and 255")
+	}
+	reward, ok := new(big.Int).SetString(ctx.String(RewardFlag), 10)
+	if !ok || reward.Sign() < 0 {
+		return fmt.Errorf("invalid reward: %s", ctx.String(RewardFlag))
+	}
+
+	nebulaAddress, err := addressFlag(ctx, NebulaFlag)
+	if err != nil {
+		return err
+	}
+	contractAddress, err := addressFlag(ctx, ContractFlag)
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	return deployerAction(ctx, cfg, ethClient, "Nebula subscribe", func(ethDeployer *deployer.EthDeployer) error {
+		subscription, err := ethDeployer.Subscribe(nebulaAddress, contractAddress, uint8(minConfirmations), reward, ctx.Context)
+		if err != nil {
+			return err
+		}
+
+		printSubscription(subscription)
+
+		return nil
+	})
+}
+
+func listSubscriptions(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.ValidateNode()
+	if err != nil {
+		return err
+	}
+
+	nebulaAddress, err := addressFlag(ctx, NebulaFlag)
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	subscriptions, err := deployer.NebulaSubscriptions(ethClient, nebulaAddress)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Subscriptions: %d\n", len(subscriptions))
+	for _, subscription := range subscriptions {
+		printSubscription(subscription)
+	}
+
+	return nil
+}
+
+func updateOracles(ctx *cli.Context) error {
+	oracles, err := addressesFlag(ctx, OraclesFlag)
+	if err != nil {
+		return err
+	}
+	nebulaAddress, err := addressFlag(ctx, NebulaFlag)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Oracles: %v\n", ctx.StringSlice(OraclesFlag))
+
+	var update *deployer.OraclesUpdate
+	messageHash := func(round *big.Int) common.Hash {
+		return deployer.OraclesMessageHash(oracles)
+	}
+
+	return submitSignedUpdate(ctx, "Nebula oracles update", messageHash,
+		func(cfg *config.EthereumConfig, ethClient *ethclient.Client, round *big.Int, signatures [][]byte) (deployer.SignedUpdate, error) {
+			update, err = deployer.NewOraclesUpdate(ethClient, nebulaAddress, oracles, round, signatures)
+			return update, err
+		},
+		func(ethDeployer *deployer.EthDeployer) error {
+			txHash, err := ethDeployer.UpdateOracles(update, ctx.Context)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Oracles updated: %s\n", txHash)
+
+			return nil
+		})
+}
+
+// addressFlag parses the address given with the flag.
+func addressFlag(ctx *cli.Context, flag string) (common.Address, error) {
+	address := ctx.String(flag)
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("invalid %s address: %s", flag, address)
+	}
+
+	return common.HexToAddress(address), nil
+}
+
+func printSubscription(subscription *deployer.Subscription) {
+	fmt.Printf("---------%s---------\n", subscription.ID.Hex())
+	fmt.Printf("Contract address: %s\n", subscription.ContractAddress.Hex())
+	fmt.Printf("Owner: %s\n", subscription.Owner.Hex())
+	fmt.Printf("Min confirmations: %d\n", subscription.MinConfirmations)
+	fmt.Printf("Reward: %s\n", subscription.Reward)
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	BundleFlag = "bundle"
+	FromFlag   = "from"
+
+	DefaultBundle = "ethereum-bundle.json"
+)
+
+var (
+	PlanCommand = &cli.Command{
+		Name:  "plan",
+		Usage: "Write the deployment transactions unsigned to a bundle",
+		Description: "Plans the port deployments of the config for the sender without a key: " +
+			"nonces, gas limits and fees are read from the node, contract addresses are predicted. " +
+			"Tokens must already be deployed. Fees are fixed at planning, broadcast the bundle soon after signing",
+		Action: plan,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  DirectionFlag,
+				Usage: "Port direction when the config has no port list: non-evm-based, evm-based or nebula",
+				Value: NonEvmBasedDirection,
+			},
+			&cli.StringFlag{
+				Name:  ExtractorFlag,
+				Usage: "Nebula data type: int64, string or bytes, the config extractor or bytes by default",
+			},
+			&cli.StringFlag{
+				Name:     FromFlag,
+				Usage:    "Address of the key that signs the bundle",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  BundleFlag,
+				Usage: "File to write the unsigned bundle to",
+				Value: DefaultBundle,
+			},
+		}, NetworkFlags...),
+	}
+
+	SignCommand = &cli.Command{
+		Name:        "sign",
+		Usage:       "Sign a bundle offline",
+		Description: "Signs every transaction of the bundle with the deployer key, no node connection is made",
+		Action:      sign,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  BundleFlag,
+				Usage: "Bundle file to sign",
+				Value: DefaultBundle,
+			},
+			&cli.StringFlag{
+				Name:  OutputFlag,
+				Usage: "File to write the signed bundle to, the bundle file by default",
+			},
+		}, KeyFlags...),
+	}
+
+	BroadcastCommand = &cli.Command{
+		Name:        "broadcast",
+		Usage:       "Send a signed bundle",
+		Description: "Sends the signed transactions in order, waits for every receipt and writes the manifest. A rerun skips mined transactions",
+		Action:      broadcast,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  BundleFlag,
+				Usage: "Signed bundle file",
+				Value: DefaultBundle,
+			},
+			&cli.StringFlag{
+				Name:  ManifestFlag,
+				Usage: "File to write the deployment manifest to",
+				Value: DefaultManifest,
+			},
+		}, NetworkFlags...),
+	}
+)
+
+func plan(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.Validate(ctx.String(DirectionFlag))
+	if err != nil {
+		return err
+	}
+
+	from := ctx.String(FromFlag)
+	if !common.IsHexAddress(from) {
+		return fmt.Errorf("invalid sender address: %s", from)
+	}
+
+	ports, err := plannedPorts(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	policy, err := gasPolicy(cfg.GasPolicy)
+	if err != nil {
+		return err
+	}
+
+	planner, err := deployer.NewPlanner(ethClient, common.HexToAddress(from), big.NewInt(cfg.ChainID), ctx.Context)
+	if err != nil {
+		return err
+	}
+	planner.Deployer().SetGasPolicy(policy)
+
+	err = planPorts(ctx, cfg, planner, ports)
+	if err != nil {
+		return err
+	}
+
+	bundle := planner.Bundle()
+	err = bundle.Save(ctx.String(BundleFlag))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Transactions: %d\n", len(bundle.Transactions))
+	fmt.Printf("Bundle: %s\n", ctx.String(BundleFlag))
+
+	return nil
+}
+
+// plannedPorts returns the ports of the config, which must use existing tokens.
+func plannedPorts(ctx *cli.Context, cfg *config.EthereumConfig) ([]*portDeployment, error) {
+	ports, err := portDeployments(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, port := range ports {
+		if !port.NebulaOnly && port.TokenAddress == "" {
+			return nil, fmt.Errorf("port %s: test tokens cannot be planned, deploy the token and set ExistingTokenAddress", port.Name)
+		}
+	}
+
+	return ports, nil
+}
+
+// planPorts adds the deployment of every port to the plan, as deploy runs it.
+func planPorts(ctx *cli.Context, cfg *config.EthereumConfig, planner *deployer.Planner, ports []*portDeployment) error {
+	create2, err := create2Deployment(cfg.Create2)
+	if err != nil {
+		return err
+	}
+	err = planner.SetCreate2(create2, ctx.Context)
+	if err != nil {
+		return err
+	}
+
+	_, err = deployPorts(ctx, cfg, planner.Deployer(), ports, nil)
+
+	return err
+}
+
+func sign(ctx *cli.Context) error {
+	bundle, err := deployer.LoadBundle(ctx.String(BundleFlag))
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := loadPrivateKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, planned := range bundle.Transactions {
+		printBundleTx(planned)
+	}
+
+	err = bundle.Sign(privateKey)
+	if err != nil {
+		return err
+	}
+
+	output := ctx.String(OutputFlag)
+	if output == "" {
+		output = ctx.String(BundleFlag)
+	}
+
+	err = bundle.Save(output)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Signed transactions: %d\n", len(bundle.Transactions))
+	fmt.Printf("Bundle: %s\n", output)
+
+	return nil
+}
+
+func broadcast(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.ValidateNode()
+	if err != nil {
+		return err
+	}
+
+	bundle, err := deployer.LoadBundle(ctx.String(BundleFlag))
+	if err != nil {
+		return err
+	}
+	if bundle.ChainID != big.NewInt(cfg.ChainID).String() {
+		return fmt.Errorf("bundle is planned for chain %s, config chain id is %d", bundle.ChainID, cfg.ChainID)
+	}
+
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	ethDeployer := deployer.NewEthDeployer(ethClient, &bind.TransactOpts{From: common.HexToAddress(bundle.From)})
+	defer saveManifest(ctx, cfg, ethDeployer)
+
+	return ethDeployer.Broadcast(bundle, ctx.Context)
+}
+
+// printBundleTx shows what a planned transaction does before it is signed.
+func printBundleTx(planned *deployer.BundleTx) {
+	tx := planned.Transaction
+
+	to := "new contract " + planned.Address
+	selector := "creation"
+	if tx.To() != nil {
+		to = tx.To().Hex()
+		selector = "none"
+		if len(tx.Data()) >= 4 {
+			selector = hexutil.Encode(tx.Data()[:4])
+		}
+	}
+
+	fmt.Printf("%s %s: nonce %d, to %s, value %s wei, selector %s, gas %d, fee cap %s gwei\n",
+		planned.Contract, planned.Step, tx.Nonce(), to, tx.Value(), selector, tx.Gas(), deployer.FormatAmount(tx.GasFeeCap(), 9))
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	ContractsFlag = "contracts"
+	NewOwnerFlag  = "new-owner"
+)
+
+var (
+	TransferOwnershipCommand = &cli.Command{
+		Name:        "transfer-ownership",
+		Usage:       "Transfer deployed contracts to a new owner",
+		Description: "Transfers every ownable contract and confirms the new owner, contracts without an owner are skipped",
+		Action:      transferOwnership,
+		Flags: append(append(append([]cli.Flag{
+			&cli.StringSliceFlag{
+				Name:     ContractsFlag,
+				Usage:    "Contract addresses, comma separated",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     NewOwnerFlag,
+				Usage:    "Address of the new owner",
+				Required: true,
+			},
+		}, NetworkFlags...), KeyFlags...), safeFlags...),
+	}
+)
+
+func transferOwnership(ctx *cli.Context) error {
+	var contracts []common.Address
+	for _, contract := range ctx.StringSlice(ContractsFlag) {
+		if !common.IsHexAddress(contract) {
+			return fmt.Errorf("invalid contract address: %s", contract)
+		}
+		contracts = append(contracts, common.HexToAddress(contract))
+	}
+
+	if !common.IsHexAddress(ctx.String(NewOwnerFlag)) {
+		return fmt.Errorf("invalid new owner address: %s", ctx.String(NewOwnerFlag))
+	}
+	newOwner := common.HexToAddress(ctx.String(NewOwnerFlag))
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.ValidateNode()
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	return deployerAction(ctx, cfg, ethClient, "Ownership transfer", func(ethDeployer *deployer.EthDeployer) error {
+		return ethDeployer.TransferOwnership(contracts, newOwner, ctx.Context)
+	})
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	DefaultSummary = "ethereum-summary.json"
+)
+
+// portDeployment is a single port of the run, from the config port list or the top level config.
+type portDeployment struct {
+	Name string
+	// NebulaOnly deploys the nebula without a port and token
+	NebulaOnly     bool
+	PortType       deployer.PortType
+	TokenAddress   string
+	TestToken      *config.TestTokenConfig
+	DataType       deployer.ExtractorType
+	Oracles        []common.Address
+	BftCoefficient int
+	Subscription   *deployer.Subscription
+}
+
+// PortSummary is the outcome of a port deployment in the combined summary.
+type PortSummary struct {
+	Name          string
+	PortType      string
+	PortAddress   string `json:",omitempty"`
+	NebulaAddress string `json:",omitempty"`
+	TokenAddress  string `json:",omitempty"`
+	Error         string `json:",omitempty"`
+}
+
+// Kind is the port type, or Nebula for a nebula without a port.
+func (port *portDeployment) Kind() string {
+	if port.NebulaOnly {
+		return "Nebula"
+	}
+
+	return port.PortType.Format()
+}
+
+func addresses(hexAddresses []string) []common.Address {
+	var result []common.Address
+	for _, address := range hexAddresses {
+		result = append(result, common.HexToAddress(address))
+	}
+
+	return result
+}
+
+// portDeployments resolves the ports of the run. Without a port list in the config
+// the single port takes the top level token and is named after the direction.
+// The extractor of a port comes from the port config, --extractor or the top level config.
+func portDeployments(ctx *cli.Context, cfg *config.EthereumConfig) ([]*portDeployment, error) {
+	defaultExtractor := cfg.Extractor
+	if ctx.IsSet(ExtractorFlag) {
+		defaultExtractor = ctx.String(ExtractorFlag)
+	}
+
+	var ports []*portDeployment
+	for _, port := range cfg.PortList(ctx.String(DirectionFlag)) {
+		nebulaOnly := port.Direction == config.NebulaDirection
+
+		var portType deployer.PortType
+		var err error
+		if !nebulaOnly {
+			portType, err = directionPortType(port.Direction)
+			if err != nil {
+				return nil, fmt.Errorf("port %s: %w", port.Name, err)
+			}
+		}
+
+		extractor := port.Extractor
+		if extractor == "" {
+			extractor = defaultExtractor
+		}
+
+		dataType, err := portDataType(extractor, nebulaOnly, portType)
+		if err != nil {
+			return nil, fmt.Errorf("port %s: %w", port.Name, err)
+		}
+
+		oracles := port.Oracles
+		if len(oracles) == 0 {
+			oracles = cfg.ConsulsAddress
+		}
+
+		bftCoefficient := port.BftCoefficient
+		if bftCoefficient == 0 {
+			bftCoefficient = cfg.GravityBftCoefficient
+		}
+
+		minConfirmations := port.MinConfirmations
+		if minConfirmations == 0 {
+			minConfirmations = cfg.MinConfirmations
+		}
+		reward := port.Reward
+		if reward == "" {
+			reward = cfg.Reward
+		}
+
+		subscription, err := portSubscription(minConfirmations, reward)
+		if err != nil {
+			return nil, fmt.Errorf("port %s: %w", port.Name, err)
+		}
+
+		ports = append(ports, &portDeployment{
+			Name:           port.Name,
+			NebulaOnly:     nebulaOnly,
+			PortType:       portType,
+			TokenAddress:   port.ExistingTokenAddress,
+			TestToken:      port.TestToken,
+			DataType:       dataType,
+			Oracles:        addresses(oracles),
+			BftCoefficient: bftCoefficient,
+			Subscription:   subscription,
+		})
+	}
+
+	return ports, nil
+}
+
+// portDataType parses the extractor, bytes by default, and checks the port contract accepts it.
+func portDataType(extractor string, nebulaOnly bool, portType deployer.PortType) (deployer.ExtractorType, error) {
+	dataType := deployer.BytesType
+	if extractor != "" {
+		var err error
+		dataType, err = deployer.ParseExtractorType(extractor)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", err, extractor)
+		}
+	}
+
+	if !nebulaOnly && dataType != deployer.PortDataType {
+		return 0, fmt.Errorf("%s accepts the %s extractor, not %s, use the %s direction for other subscribers",
+			portType.Format(), deployer.PortDataType, dataType, config.NebulaDirection)
+	}
+
+	return dataType, nil
+}
+
+// portSubscription builds the subscription parameters, one confirmation and no reward by default.
+func portSubscription(minConfirmations uint8, reward string) (*deployer.Subscription, error) {
+	subscription := deployer.DefaultSubscription()
+	if minConfirmations != 0 {
+		subscription.MinConfirmations = minConfirmations
+	}
+	if reward != "" {
+		value, ok := new(big.Int).SetString(reward, 10)
+		if !ok || value.Sign() < 0 {
+			return nil, fmt.Errorf("invalid subscription reward: %s", reward)
+		}
+		subscription.Reward = value
+	}
+
+	return subscription, nil
+}
+
+// deployPorts deploys every port of the run. A failed port is reported in the
+// summary and does not stop the remaining ports.
+func deployPorts(ctx *cli.Context, cfg *config.EthereumConfig, ethDeployer *deployer.EthDeployer, ports []*portDeployment, state *deployer.DeployState) ([]*PortSummary, error) {
+	var summaries []*PortSummary
+	var failed int
+	for _, port := range ports {
+		fmt.Printf("=========%s=========\n", port.Name)
+
+		portState := new(deployer.PortState)
+		if state != nil {
+			portState = state.Port(port.Name)
+		}
+
+		summary := &PortSummary{
+			Name:     port.Name,
+			PortType: port.Kind(),
+		}
+		summaries = append(summaries, summary)
+
+		gatewayPort, err := deployPort(ctx, cfg, ethDeployer, port, portState)
+		for _, record := range portState.Contracts {
+			record.Port = port.Name
+		}
+		if err != nil {
+			failed++
+			summary.Error = err.Error()
+			fmt.Printf("Port %s failed: %v\n", port.Name, err)
+			continue
+		}
+
+		summary.PortAddress = gatewayPort.PortAddress
+		summary.NebulaAddress = gatewayPort.NebulaAddress
+		summary.TokenAddress = gatewayPort.ERC20Address
+	}
+
+	printSummary(summaries)
+
+	if failed > 0 {
+		return summaries, fmt.Errorf("%d of %d ports failed", failed, len(ports))
+	}
+
+	return summaries, nil
+}
+
+func printSummary(summaries []*PortSummary) {
+	fmt.Println("---------Summary---------")
+
+	for _, summary := range summaries {
+		if summary.Error != "" {
+			fmt.Printf("%s (%s): FAILED %s\n", summary.Name, summary.PortType, summary.Error)
+			continue
+		}
+
+		if summary.PortAddress == "" {
+			fmt.Printf("%s (%s): nebula %s\n", summary.Name, summary.PortType, summary.NebulaAddress)
+			continue
+		}
+
+		fmt.Printf("%s (%s): port %s, nebula %s, token %s\n",
+			summary.Name, summary.PortType, summary.PortAddress, summary.NebulaAddress, summary.TokenAddress)
+	}
+}
+
+func saveSummary(ctx *cli.Context, summaries []*PortSummary) {
+	path := networkFile(ctx, SummaryFlag)
+	if path == "" || summaries == nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(summaries, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		fmt.Printf("Failed to save summary: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Summary: %s\n", path)
+}
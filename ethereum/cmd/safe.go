@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strings"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	SafeFlag     = "safe"
+	ProposalFlag = "proposal"
+	BatchFlag    = "batch"
+
+	DefaultProposal = "ethereum-safe-proposal.json"
+)
+
+var (
+	// safeFlags turn an admin command into a Safe proposal, written instead of sending from the deployer key.
+	safeFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:  SafeFlag,
+			Usage: "Safe address, writes Safe transactions to propose instead of sending from the deployer key",
+		},
+		&cli.StringFlag{
+			Name:  ProposalFlag,
+			Usage: "Transaction Builder file to write the Safe proposal to, numbered when there are several",
+			Value: DefaultProposal,
+		},
+		&cli.BoolFlag{
+			Name:  BatchFlag,
+			Usage: "Propose a single batch, executed as one MultiSend transaction, instead of one transaction per step",
+		},
+	}
+
+	ProposeCommand = &cli.Command{
+		Name:  "propose",
+		Usage: "Write the deployment as a Safe proposal",
+		Description: "Plans the port deployments of the config as Safe Transaction Builder batches: " +
+			"the Safe creates the contracts through the CREATE2 factory of the config or the default one. Tokens must already be deployed",
+		Action: propose,
+		Flags: append(append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  DirectionFlag,
+				Usage: "Port direction when the config has no port list: non-evm-based, evm-based or nebula",
+				Value: NonEvmBasedDirection,
+			},
+			&cli.StringFlag{
+				Name:  ExtractorFlag,
+				Usage: "Nebula data type: int64, string or bytes, the config extractor or bytes by default",
+			},
+		}, NetworkFlags...), safeFlags...),
+	}
+)
+
+// safePlanner plans the transactions of the Safe given with --safe.
+func safePlanner(ctx *cli.Context, cfg *config.EthereumConfig, ethClient *ethclient.Client) (*deployer.Planner, error) {
+	safe := ctx.String(SafeFlag)
+	if !common.IsHexAddress(safe) {
+		return nil, fmt.Errorf("invalid safe address: %s", safe)
+	}
+
+	fmt.Printf("Safe: %s\n", safe)
+
+	return deployer.NewSafePlanner(ethClient, common.HexToAddress(safe), big.NewInt(cfg.ChainID), ctx.Context)
+}
+
+// saveSafeProposal writes the planned transactions as Safe Transaction Builder batches.
+func saveSafeProposal(ctx *cli.Context, planner *deployer.Planner, name string) error {
+	proposals, err := planner.SafeProposals(name, ctx.Bool(BatchFlag))
+	if err != nil {
+		return err
+	}
+	if len(proposals) == 0 {
+		fmt.Println("Nothing to propose")
+		return nil
+	}
+
+	for i, proposal := range proposals {
+		filename := ctx.String(ProposalFlag)
+		if len(proposals) > 1 {
+			extension := filepath.Ext(filename)
+			filename = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(filename, extension), i+1, extension)
+		}
+
+		err = proposal.Save(filename)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Proposal %s: %s\n", proposal.Meta.Description, filename)
+	}
+
+	return nil
+}
+
+// deployerAction runs the action with the deployer key, or plans it for the Safe given with --safe
+// and writes the proposal.
+func deployerAction(ctx *cli.Context, cfg *config.EthereumConfig, ethClient *ethclient.Client, name string,
+	action func(ethDeployer *deployer.EthDeployer) error) error {
+
+	if ctx.String(SafeFlag) == "" {
+		ethDeployer, err := keyedDeployer(ctx, cfg, ethClient)
+		if err != nil {
+			return err
+		}
+
+		return action(ethDeployer)
+	}
+
+	planner, err := safePlanner(ctx, cfg, ethClient)
+	if err != nil {
+		return err
+	}
+
+	err = action(planner.Deployer())
+	if err != nil {
+		return err
+	}
+
+	return saveSafeProposal(ctx, planner, name)
+}
+
+func propose(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.Validate(ctx.String(DirectionFlag))
+	if err != nil {
+		return err
+	}
+	if ctx.String(SafeFlag) == "" {
+		return fmt.Errorf("safe address is not set, use --%s", SafeFlag)
+	}
+
+	ports, err := plannedPorts(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	planner, err := safePlanner(ctx, cfg, ethClient)
+	if err != nil {
+		return err
+	}
+
+	err = planPorts(ctx, cfg, planner, ports)
+	if err != nil {
+		return err
+	}
+
+	return saveSafeProposal(ctx, planner, "Gateway deployment")
+}
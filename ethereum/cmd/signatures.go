@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	SignaturesFlag  = "signatures"
+	RoundFlag       = "round"
+	MessageOnlyFlag = "message-only"
+)
+
+// signatureFlags are shared by the updates the consuls sign.
+var signatureFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:     RoundFlag,
+		Usage:    "Round of the update",
+		Required: true,
+	},
+	&cli.StringSliceFlag{
+		Name:  SignaturesFlag,
+		Usage: "Files with consul signatures, one hex signature per line, - for stdin",
+	},
+	&cli.BoolFlag{
+		Name:  MessageOnlyFlag,
+		Usage: "Print the message hash to sign and exit",
+	},
+}
+
+// submitSignedUpdate prints the message hash the consuls sign for the update at the round and, unless only
+// the message is asked for, reads their signatures, builds the update on the node and submits it
+// once the quorum is reached.
+func submitSignedUpdate(ctx *cli.Context, name string, messageHash func(round *big.Int) common.Hash,
+	newUpdate func(cfg *config.EthereumConfig, ethClient *ethclient.Client, round *big.Int, signatures [][]byte) (deployer.SignedUpdate, error),
+	submit func(ethDeployer *deployer.EthDeployer) error) error {
+
+	round, ok := new(big.Int).SetString(ctx.String(RoundFlag), 10)
+	if !ok || round.Sign() < 0 {
+		return fmt.Errorf("invalid round: %s", ctx.String(RoundFlag))
+	}
+
+	fmt.Printf("Round: %s\n", round)
+	fmt.Printf("Message hash: %s\n", messageHash(round).Hex())
+
+	if ctx.Bool(MessageOnlyFlag) {
+		return nil
+	}
+	if len(ctx.StringSlice(SignaturesFlag)) == 0 {
+		return fmt.Errorf("no signatures, use --%s", SignaturesFlag)
+	}
+
+	signatures, err := readSignatures(ctx.StringSlice(SignaturesFlag))
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.ValidateNode()
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	update, err := newUpdate(cfg, ethClient, round, signatures)
+	if err != nil {
+		return err
+	}
+
+	printConsulSignatures(update.Approvals())
+
+	err = update.CheckQuorum()
+	if err != nil {
+		return err
+	}
+
+	return deployerAction(ctx, cfg, ethClient, name, submit)
+}
+
+func printConsulSignatures(signatures *deployer.ConsulSignatures, quorum int) {
+	for i, consul := range signatures.Consuls {
+		status := "missing"
+		if signatures.Signed[i] {
+			status = "signed"
+		}
+		fmt.Printf("Consul %s: %s\n", consul.Hex(), status)
+	}
+	fmt.Printf("Signatures: %d of %d required\n", signatures.Count(), quorum)
+}
+
+// addressesFlag parses the comma separated addresses given with the flag.
+func addressesFlag(ctx *cli.Context, flag string) ([]common.Address, error) {
+	var addresses []common.Address
+	for _, address := range ctx.StringSlice(flag) {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid %s address: %s", flag, address)
+		}
+		addresses = append(addresses, common.HexToAddress(address))
+	}
+
+	return addresses, nil
+}
+
+// readSignatures reads detached consul signatures, one 0x prefixed hex signature per line.
+// Empty lines and lines starting with # are skipped, - reads from stdin.
+func readSignatures(paths []string) ([][]byte, error) {
+	var signatures [][]byte
+	for _, path := range paths {
+		var reader io.Reader = os.Stdin
+		if path != "-" {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			reader = file
+		}
+
+		scanner := bufio.NewScanner(reader)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+
+			signature, err := hexutil.Decode(text)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid signature: %w", path, line, err)
+			}
+			signatures = append(signatures, signature)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return signatures, nil
+}
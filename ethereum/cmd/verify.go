@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/config"
+	"github.com/Gravity-Tech/gateway-deployer/ethereum/deployer"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NebulaFlag = "nebula"
+	PortFlag   = "port"
+)
+
+var (
+	VerifyCommand = &cli.Command{
+		Name:        "verify",
+		Usage:       "Verify deployed contracts",
+		Description: "Reads the nebula and port settings on-chain and compares them with the config. Port and nebula addresses are taken from the manifest unless --port and --nebula are set",
+		Action:      verify,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  ManifestFlag,
+				Value: DefaultManifest,
+			},
+			&cli.StringFlag{
+				Name:  DirectionFlag,
+				Usage: "Port direction when the config has no port list: non-evm-based, evm-based or nebula",
+				Value: NonEvmBasedDirection,
+			},
+			&cli.StringFlag{
+				Name:  ExtractorFlag,
+				Usage: "Nebula data type: int64, string or bytes, the config extractor or bytes by default",
+			},
+			&cli.StringFlag{
+				Name:  NebulaFlag,
+				Usage: "Nebula address",
+			},
+			&cli.StringFlag{
+				Name:  PortFlag,
+				Usage: "Port address",
+			},
+		}, NetworkFlags...),
+	}
+)
+
+func verify(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	err = cfg.ValidateNode()
+	if err != nil {
+		return err
+	}
+
+	ports, err := expectedPorts(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := dialNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	if cfg.ExistingGravityAddress != "" {
+		err = checkGravity(ctx, cfg, ethClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	var failed int
+	for _, port := range ports {
+		if port.NebulaOnly {
+			fmt.Println("---------Nebula---------: ")
+		} else {
+			fmt.Printf("---------%v---------: \n", port.PortType.Format())
+			fmt.Printf("Port address: %s\n", port.PortAddress.Hex())
+		}
+		fmt.Printf("Nebula address: %s\n", port.NebulaAddress.Hex())
+
+		mismatches, err := deployer.VerifyPort(ethClient, port, ctx.Context)
+		if err != nil {
+			return err
+		}
+
+		for _, mismatch := range mismatches {
+			fmt.Printf("MISMATCH %s\n", mismatch)
+		}
+		if len(mismatches) == 0 {
+			fmt.Println("OK")
+		}
+		failed += len(mismatches)
+	}
+
+	if failed > 0 {
+		return cli.Exit(fmt.Sprintf("%d mismatches found", failed), 1)
+	}
+
+	return nil
+}
+
+// expectedPorts builds the expectations from the config, with the addresses
+// from the flags or from every port of the manifest.
+func expectedPorts(ctx *cli.Context, cfg *config.EthereumConfig) ([]*deployer.ExpectedPort, error) {
+	newExpected := func(port *portDeployment, portAddress string, nebulaAddress string, tokenAddress string) *deployer.ExpectedPort {
+		return &deployer.ExpectedPort{
+			NebulaOnly:     port.NebulaOnly,
+			PortType:       port.PortType,
+			PortAddress:    common.HexToAddress(portAddress),
+			NebulaAddress:  common.HexToAddress(nebulaAddress),
+			GravityAddress: common.HexToAddress(cfg.ExistingGravityAddress),
+			TokenAddress:   common.HexToAddress(tokenAddress),
+			Oracles:        port.Oracles,
+			BftCoefficient: port.BftCoefficient,
+			DataType:       port.DataType,
+			Subscription:   port.Subscription,
+		}
+	}
+
+	deployments, err := portDeployments(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.IsSet(PortFlag) || ctx.IsSet(NebulaFlag) {
+		if len(deployments) != 1 {
+			return nil, fmt.Errorf("--%s and --%s need a config with a single port", PortFlag, NebulaFlag)
+		}
+		if !ctx.IsSet(NebulaFlag) {
+			return nil, fmt.Errorf("--%s is required", NebulaFlag)
+		}
+		if !deployments[0].NebulaOnly && !ctx.IsSet(PortFlag) {
+			return nil, fmt.Errorf("both --%s and --%s are required", PortFlag, NebulaFlag)
+		}
+
+		return []*deployer.ExpectedPort{
+			newExpected(deployments[0], ctx.String(PortFlag), ctx.String(NebulaFlag), deployments[0].TokenAddress),
+		}, nil
+	}
+
+	manifest, err := deployer.LoadManifest(networkFile(ctx, ManifestFlag))
+	if err != nil {
+		return nil, err
+	}
+
+	var ports []*deployer.ExpectedPort
+	for _, contract := range manifest.Contracts {
+		var portType deployer.PortType
+		switch contract.Name {
+		case deployer.IBPort.ContractName():
+			portType = deployer.IBPort
+		case deployer.LUPort.ContractName():
+			portType = deployer.LUPort
+		case "Nebula":
+		default:
+			continue
+		}
+
+		port := manifestPort(cfg, deployments, contract, portType)
+		if port == nil {
+			fmt.Printf("Skip %s %s: port %s is not in the config\n", contract.Name, contract.Address, contract.Port)
+			continue
+		}
+		// the nebula of a port is checked with the port
+		if contract.Name == "Nebula" {
+			if port.NebulaOnly {
+				ports = append(ports, newExpected(port, "", contract.Address, ""))
+			}
+			continue
+		}
+		if port.NebulaOnly {
+			fmt.Printf("Skip %s %s: port %s is a nebula in the config\n", contract.Name, contract.Address, contract.Port)
+			continue
+		}
+
+		nebulaAddress, _ := contract.ConstructorArgs["nebula"].(string)
+		tokenAddress := port.TokenAddress
+		if tokenAddress == "" {
+			tokenAddress, _ = contract.ConstructorArgs["tokenAddress"].(string)
+		}
+
+		ports = append(ports, newExpected(port, contract.Address, nebulaAddress, tokenAddress))
+	}
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("no ports or nebulae in manifest %s", networkFile(ctx, ManifestFlag))
+	}
+
+	return ports, nil
+}
+
+// manifestPort finds the config port of a manifest record by its port name.
+// Without a port list in the config every record is checked against the top level config.
+func manifestPort(cfg *config.EthereumConfig, deployments []*portDeployment, contract *deployer.ContractRecord, portType deployer.PortType) *portDeployment {
+	if len(cfg.Ports) == 0 {
+		port := *deployments[0]
+		port.PortType = portType
+		return &port
+	}
+
+	for _, port := range deployments {
+		if port.Name == contract.Port {
+			return port
+		}
+	}
+
+	return nil
+}
@@ -1,18 +1,123 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// AddressValidator is implemented by configs whose addresses are checked after loading.
+type AddressValidator interface {
+	ValidateAddresses() error
+}
+
+// ParseConfig strictly decodes the json config: unknown fields are rejected,
+// ${ENV} references in string values are replaced with the environment variable
+// and the addresses are checked.
 func ParseConfig(filename string, config interface{}) error {
 	file, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return err
 	}
-	if err := json.Unmarshal(file, config); err != nil {
+
+	decoder := json.NewDecoder(bytes.NewReader(file))
+	decoder.UseNumber()
+
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return fmt.Errorf("config %s: %w", filename, err)
+	}
+
+	raw, err = interpolate(raw, "")
+	if err != nil {
+		return fmt.Errorf("config %s: %w", filename, err)
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
 		return err
 	}
 
+	decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return fmt.Errorf("config %s: %w", filename, err)
+	}
+
+	if validator, ok := config.(AddressValidator); ok {
+		if err := validator.ValidateAddresses(); err != nil {
+			return fmt.Errorf("config %s: %w", filename, err)
+		}
+	}
+
+	return nil
+}
+
+// interpolate replaces ${ENV} references in every string of the decoded json.
+func interpolate(value interface{}, field string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		var err error
+		result := envPattern.ReplaceAllStringFunc(v, func(ref string) string {
+			name := envPattern.FindStringSubmatch(ref)[1]
+			env, ok := os.LookupEnv(name)
+			if !ok && err == nil {
+				err = fmt.Errorf("field %s: environment variable %s is not set", field, name)
+			}
+			return env
+		})
+		return result, err
+	case map[string]interface{}:
+		for key, item := range v {
+			path := key
+			if field != "" {
+				path = field + "." + key
+			}
+
+			result, err := interpolate(item, path)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = result
+		}
+	case []interface{}:
+		for i, item := range v {
+			result, err := interpolate(item, fmt.Sprintf("%s[%d]", field, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = result
+		}
+	}
+
+	return value, nil
+}
+
+// validateAddress checks an address field, mixed case addresses must match the EIP-55 checksum.
+func validateAddress(field string, address string) error {
+	if address == "" {
+		return nil
+	}
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("field %s: invalid address %s", field, address)
+	}
+
+	checksummed := common.HexToAddress(address).Hex()
+	hex := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	if hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+		return nil
+	}
+	if "0x"+hex != checksummed {
+		return fmt.Errorf("field %s: invalid address checksum %s, expected %s", field, address, checksummed)
+	}
+
 	return nil
 }
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	filename := filepath.Join(t.TempDir(), "ethereum-cfg.json")
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return filename
+}
+
+func TestParseConfigExample(t *testing.T) {
+	cfg := new(EthereumConfig)
+	if err := ParseConfig("../ethereum-cfg-example.json", cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseConfigUnknownField(t *testing.T) {
+	filename := writeConfig(t, `{"NodeUrl": "http://localhost:8545", "PrivKey": "00"}`)
+
+	err := ParseConfig(filename, new(EthereumConfig))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "PrivKey") || !strings.Contains(err.Error(), filename) {
+		t.Errorf("error does not name the field and file: %v", err)
+	}
+}
+
+func TestParseConfigEnv(t *testing.T) {
+	os.Setenv("TEST_CONFIG_NODE", "localhost:8545")
+	defer os.Unsetenv("TEST_CONFIG_NODE")
+
+	filename := writeConfig(t, `{"NodeUrl": "http://${TEST_CONFIG_NODE}", "GasPolicy": {"GasLimits": {"Nebula": 5000000}}}`)
+
+	cfg := new(EthereumConfig)
+	if err := ParseConfig(filename, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.NodeUrl != "http://localhost:8545" {
+		t.Errorf("expected interpolated node url, got %s", cfg.NodeUrl)
+	}
+	if cfg.GasPolicy.GasLimits["Nebula"] != 5000000 {
+		t.Errorf("expected nebula gas limit 5000000, got %d", cfg.GasPolicy.GasLimits["Nebula"])
+	}
+}
+
+func TestParseConfigMissingEnv(t *testing.T) {
+	os.Unsetenv("TEST_CONFIG_MISSING")
+	filename := writeConfig(t, `{"Explorer": {"ApiUrl": "http://localhost", "ApiKey": "${TEST_CONFIG_MISSING}"}}`)
+
+	err := ParseConfig(filename, new(EthereumConfig))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "Explorer.ApiKey") || !strings.Contains(err.Error(), "TEST_CONFIG_MISSING") {
+		t.Errorf("error does not name the field and variable: %v", err)
+	}
+}
+
+func TestParseConfigAddressChecksum(t *testing.T) {
+	tests := []struct {
+		address string
+		valid   bool
+	}{
+		{"0x4e59b44847b379578588920cA78FbF26c0B4956C", true},
+		{"0x4e59b44847b379578588920ca78fbf26c0b4956c", true},
+		{"0x4e59b44847b379578588920cA78FbF26c0B4956c", false},
+		{"0x4e59b44847b379578588920cA78FbF26c0B495", false},
+	}
+
+	for _, test := range tests {
+		filename := writeConfig(t, `{"ConsulsAddress": ["`+test.address+`"]}`)
+
+		err := ParseConfig(filename, new(EthereumConfig))
+		if test.valid && err != nil {
+			t.Errorf("%s: %v", test.address, err)
+		}
+		if !test.valid {
+			if err == nil {
+				t.Errorf("%s: expected error", test.address)
+			} else if !strings.Contains(err.Error(), "ConsulsAddress[0]") {
+				t.Errorf("%s: error does not name the field: %v", test.address, err)
+			}
+		}
+	}
+}
+
+func TestValidatePortBft(t *testing.T) {
+	consuls := []string{"0x4e59b44847b379578588920cA78FbF26c0B4956C"}
+	tests := []struct {
+		port  PortConfig
+		valid bool
+	}{
+		{PortConfig{Direction: NebulaDirection}, true},
+		{PortConfig{Direction: NebulaDirection, BftCoefficient: 2}, false},
+		{PortConfig{Direction: NebulaDirection, BftCoefficient: 2, Oracles: []string{consuls[0], consuls[0]}}, true},
+		{PortConfig{Direction: NebulaDirection, BftCoefficient: 3, Oracles: []string{consuls[0], consuls[0]}}, false},
+	}
+
+	for i, test := range tests {
+		err := test.port.Validate(consuls, 1)
+		if test.valid && err != nil {
+			t.Errorf("%d: %v", i, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%d: expected error", i)
+		}
+	}
+}
+
+func TestValidateCreate2Salt(t *testing.T) {
+	tests := []struct {
+		salt  string
+		valid bool
+	}{
+		{"gateway", true},
+		{"0x" + strings.Repeat("01", 32), true},
+		{"0x01", false},
+		{"0x" + strings.Repeat("01", 33), false},
+		{"0x" + strings.Repeat("zz", 32), false},
+		{"0x", false},
+	}
+
+	for _, test := range tests {
+		err := (&Create2Config{Salt: test.salt}).Validate()
+		if test.valid && err != nil {
+			t.Errorf("%s: %v", test.salt, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected error", test.salt)
+		}
+	}
+}
+
+func TestValidateTestTokenSupply(t *testing.T) {
+	tests := []struct {
+		token TestTokenConfig
+		valid bool
+	}{
+		{TestTokenConfig{Name: "Test", Symbol: "TST"}, true},
+		{TestTokenConfig{Name: "Test", Symbol: "TST", InitialSupply: "1000.5"}, true},
+		{TestTokenConfig{Name: "Test", Symbol: "TST", InitialSupply: "1.5", Decimals: 1}, true},
+		{TestTokenConfig{Name: "Test", Symbol: "TST", InitialSupply: "1.55", Decimals: 1}, false},
+		{TestTokenConfig{Name: "Test", Symbol: "TST", InitialSupply: "-1"}, false},
+		{TestTokenConfig{Name: "Test", Symbol: "TST", InitialSupply: "1e18"}, false},
+	}
+
+	for i, test := range tests {
+		err := test.token.Validate()
+		if test.valid && err != nil {
+			t.Errorf("%d: %v", i, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%d: expected error", i)
+		}
+	}
+}
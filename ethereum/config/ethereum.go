@@ -1,32 +1,363 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
 
 type EthereumConfig struct {
-	GravityBftCoefficient int
-	NodeUrl               string
-	ConsulsAddress        []string
+	GravityBftCoefficient  int
+	NodeUrl                string
+	ChainID                int64
+	NativeCurrency         string
+	ConsulsAddress         []string
 	ExistingGravityAddress string
-	ExistingTokenAddress  string
+	ExistingTokenAddress   string
+	// TestToken is deployed and used as the port token when ExistingTokenAddress is empty
+	TestToken *TestTokenConfig
+	GasPolicy *GasPolicyConfig
+	// Create2 deploys the nebula and port through a CREATE2 factory when set
+	Create2 *Create2Config
+	// Explorer is the Etherscan compatible API used to publish contract sources
+	Explorer *ExplorerConfig
+	// Extractor is the nebula data type: int64, string or bytes, bytes by default.
+	// Ports read bytes, int64 and string are for nebulae of other subscribers such as price feeds
+	Extractor string
+	// MinConfirmations of the port subscription, 1 by default
+	MinConfirmations uint8
+	// Reward of the port subscription in wei, 0 by default
+	Reward string
+	// FinalOwner receives the ownership of the deployed contracts, such as a Gnosis Safe
+	FinalOwner string
+	// TransferTokenOwnership hands an existing ownable token over to the IB port, which mints and burns it.
+	// Test tokens deployed in the run are always handed over, tokens with roles get the roles granted instead
+	TransferTokenOwnership bool
+	// Ports are deployed in a single run. The top level token and --direction are used when it is empty,
+	// --direction nebula deploys a single nebula without a port
+	Ports []*PortConfig
+	// Networks are named profiles selected with --network, in addition to the built-in ones
+	Networks map[string]*NetworkConfig
+}
+
+// PortConfig describes one of the ports deployed in a single run.
+type PortConfig struct {
+	// Name identifies the port in the state file and the summary
+	Name string
+	// Direction is evm-based, non-evm-based or nebula, which deploys only a nebula
+	// for other subscribers such as price feeds
+	Direction            string
+	ExistingTokenAddress string
+	// TestToken is deployed and used as the port token when ExistingTokenAddress is empty
+	TestToken *TestTokenConfig
+	// Extractor is the nebula data type: int64, string or bytes, the top level extractor by default
+	Extractor string
+	// Oracles of the nebula, ConsulsAddress by default
+	Oracles []string
+	// BftCoefficient of the nebula, GravityBftCoefficient by default
+	BftCoefficient int
+	// MinConfirmations and Reward of the port subscription, the top level values by default
+	MinConfirmations uint8
+	Reward           string
+}
+
+type Create2Config struct {
+	// Factory is the deterministic deployment proxy by default
+	Factory string
+	// Salt is a 0x prefixed 32 byte hex value or any string, which is hashed
+	Salt string
+}
+
+type TestTokenConfig struct {
+	Name   string
+	Symbol string
+	// Decimals are checked against the decimals of the test token contract, which are used when it is 0
+	Decimals      uint8
+	InitialSupply string
+}
+
+const (
+	LegacyTxType  = "legacy"
+	EIP1559TxType = "eip1559"
+)
+
+type GasPolicyConfig struct {
+	// TxType is legacy or eip1559, eip1559 by default
+	TxType string
+	// Fee caps in gwei
+	MaxFeePerGas         string
+	MaxPriorityFeePerGas string
+	GasPriceMultiplier   float64
+	// GasLimits overrides the gas limit per step: Gravity, Nebula, IBPort, LUPort, Token, subscribe, mint, transferOwnership, grantRole, updateOracles, updateConsuls
+	GasLimits map[string]uint64
+	// FeeCeiling is the highest fee of a single transaction in the native currency
+	FeeCeiling string
+}
+
+// NebulaDirection deploys a nebula without a port or token.
+const NebulaDirection = "nebula"
+
+// amountPattern matches decimal amounts such as 1000 or 1.5.
+var amountPattern = regexp.MustCompile(`^(\d+\.?\d*|\.\d+)$`)
+
+type ExplorerConfig struct {
+	ApiUrl string
+	ApiKey string
+	// Contracts maps a contract name (Gravity, Nebula, IBPort, LUPort, Token) to its source
+	Contracts map[string]*ContractSourceConfig
+}
+
+type ContractSourceConfig struct {
+	// SourceFile is a flattened source or a standard json input
+	SourceFile string
+	// CodeFormat is solidity-single-file or solidity-standard-json-input
+	CodeFormat       string
+	ContractName     string
+	CompilerVersion  string
+	OptimizationUsed bool
+	Runs             int
+	EVMVersion       string
+	LicenseType      int
 }
 
+// Validate checks the config for a deployment of its ports, or of the single port of the direction
+// without a port list, and the node to deploy with.
+func (cfg *EthereumConfig) Validate(direction string) error {
+	err := cfg.ValidateContracts(direction)
+	if err != nil {
+		return err
+	}
 
-func (cfg *EthereumConfig) Validate() error {
+	return cfg.ValidateNode()
+}
+
+// ValidateContracts checks the config for a deployment of its ports without the node,
+// which a dry run does not need.
+func (cfg *EthereumConfig) ValidateContracts(direction string) error {
 	if cfg.ExistingGravityAddress == "" {
 		return fmt.Errorf("gravity address is empty")
 	}
-	if cfg.ExistingTokenAddress == "" {
-		return fmt.Errorf("existing token address is empty")
+
+	names := make(map[string]bool)
+	for _, port := range cfg.PortList(direction) {
+		if port.Name == "" {
+			return fmt.Errorf("port name is empty")
+		}
+		if names[port.Name] {
+			return fmt.Errorf("duplicate port name: %s", port.Name)
+		}
+		names[port.Name] = true
+
+		err := port.Validate(cfg.ConsulsAddress, cfg.GravityBftCoefficient)
+		if err != nil {
+			return fmt.Errorf("port %s: %w", port.Name, err)
+		}
+	}
+
+	if cfg.Create2 != nil {
+		err := cfg.Create2.Validate()
+		if err != nil {
+			return err
+		}
 	}
+
+	return cfg.ValidateConsuls()
+}
+
+// PortList returns the ports of the run. Without a port list the top level token makes
+// a single port of the direction, named after it.
+func (cfg *EthereumConfig) PortList(direction string) []*PortConfig {
+	if len(cfg.Ports) > 0 {
+		return cfg.Ports
+	}
+
+	return []*PortConfig{
+		{
+			Name:                 direction,
+			Direction:            direction,
+			ExistingTokenAddress: cfg.ExistingTokenAddress,
+			TestToken:            cfg.TestToken,
+		},
+	}
+}
+
+// ValidateAddresses checks every address of the config.
+func (cfg *EthereumConfig) ValidateAddresses() error {
+	var fields [][2]string
+	add := func(field string, address string) {
+		fields = append(fields, [2]string{field, address})
+	}
+
+	add("ExistingGravityAddress", cfg.ExistingGravityAddress)
+	add("ExistingTokenAddress", cfg.ExistingTokenAddress)
+	add("FinalOwner", cfg.FinalOwner)
+	for i, consul := range cfg.ConsulsAddress {
+		add(fmt.Sprintf("ConsulsAddress[%d]", i), consul)
+	}
+	if cfg.Create2 != nil {
+		add("Create2.Factory", cfg.Create2.Factory)
+	}
+	for i, port := range cfg.Ports {
+		add(fmt.Sprintf("Ports[%d].ExistingTokenAddress", i), port.ExistingTokenAddress)
+		for j, oracle := range port.Oracles {
+			add(fmt.Sprintf("Ports[%d].Oracles[%d]", i, j), oracle)
+		}
+	}
+	for name, network := range cfg.Networks {
+		if network != nil {
+			add(fmt.Sprintf("Networks.%s.GravityAddress", name), network.GravityAddress)
+		}
+	}
+
+	for _, field := range fields {
+		err := validateAddress(field[0], field[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateToken(existingTokenAddress string, testToken *TestTokenConfig) error {
+	if existingTokenAddress != "" {
+		return nil
+	}
+	if testToken == nil {
+		return fmt.Errorf("existing token address is empty and test token is not configured")
+	}
+
+	return testToken.Validate()
+}
+
+// Validate checks the port with the consuls and the gravity bft coefficient its nebula takes by default.
+func (cfg *PortConfig) Validate(consuls []string, gravityBftCoefficient int) error {
+	if cfg.Direction == "" {
+		return fmt.Errorf("direction is empty")
+	}
+	if cfg.BftCoefficient < 0 {
+		return fmt.Errorf("bft coefficient cannot be negative")
+	}
+
+	bftCoefficient := cfg.BftCoefficient
+	if bftCoefficient == 0 {
+		bftCoefficient = gravityBftCoefficient
+	}
+	if len(cfg.Oracles) > 0 && bftCoefficient > len(cfg.Oracles) {
+		return fmt.Errorf("bft coefficient %d is unreachable with %d oracles", bftCoefficient, len(cfg.Oracles))
+	}
+	if len(cfg.Oracles) == 0 && bftCoefficient > len(consuls) {
+		return fmt.Errorf("bft coefficient %d is unreachable with %d consuls as oracles", bftCoefficient, len(consuls))
+	}
+	if cfg.Direction == NebulaDirection {
+		return nil
+	}
+
+	return validateToken(cfg.ExistingTokenAddress, cfg.TestToken)
+}
+
+func (cfg *GasPolicyConfig) Validate() error {
+	switch cfg.TxType {
+	case "", LegacyTxType, EIP1559TxType:
+	default:
+		return fmt.Errorf("unknown transaction type: %s", cfg.TxType)
+	}
+	if cfg.GasPriceMultiplier < 0 {
+		return fmt.Errorf("gas price multiplier cannot be negative")
+	}
+
+	return nil
+}
+
+// ValidateGravity checks the fields required to deploy a new gravity contract.
+func (cfg *EthereumConfig) ValidateGravity() error {
+	err := cfg.ValidateConsuls()
+	if err != nil {
+		return err
+	}
+
+	return cfg.ValidateNode()
+}
+
+// ValidateConsuls checks the consuls and the bft coefficient of the gravity contract.
+func (cfg *EthereumConfig) ValidateConsuls() error {
 	if len(cfg.ConsulsAddress) == 0 {
 		return fmt.Errorf("consuls list is empty")
 	}
+	if cfg.GravityBftCoefficient <= 0 {
+		return fmt.Errorf("bft coefficient cannot be less than 1")
+	}
+
+	return nil
+}
+
+// ValidateNode checks the fields required to connect to the node.
+func (cfg *EthereumConfig) ValidateNode() error {
 	if cfg.NodeUrl == "" {
 		return fmt.Errorf("node url is empty")
 	}
-	if cfg.GravityBftCoefficient <= 0 {
-		return fmt.Errorf("bft coefficient cannot be less than 1")
+	if cfg.ChainID <= 0 {
+		return fmt.Errorf("chain id is empty")
+	}
+	if cfg.GasPolicy != nil {
+		err := cfg.GasPolicy.Validate()
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (cfg *TestTokenConfig) Validate() error {
+	if cfg.Name == "" {
+		return fmt.Errorf("test token name is empty")
+	}
+	if cfg.Symbol == "" {
+		return fmt.Errorf("test token symbol is empty")
+	}
+	if cfg.InitialSupply != "" && !amountPattern.MatchString(cfg.InitialSupply) {
+		return fmt.Errorf("invalid test token initial supply: %s", cfg.InitialSupply)
+	}
+	if i := strings.IndexByte(cfg.InitialSupply, '.'); i >= 0 && cfg.Decimals != 0 && len(cfg.InitialSupply)-i-1 > int(cfg.Decimals) {
+		return fmt.Errorf("test token initial supply %s has more than %d decimal places", cfg.InitialSupply, cfg.Decimals)
+	}
+
+	return nil
+}
+
+// Validate checks the salt, which has to be 32 bytes when it is given as hex.
+func (cfg *Create2Config) Validate() error {
+	if !strings.HasPrefix(cfg.Salt, "0x") {
+		return nil
+	}
+
+	salt, err := hexutil.Decode(cfg.Salt)
+	if err != nil {
+		return fmt.Errorf("invalid CREATE2 salt %s: %w", cfg.Salt, err)
+	}
+	if len(salt) != common.HashLength {
+		return fmt.Errorf("CREATE2 salt %s has %d bytes, expected %d", cfg.Salt, len(salt), common.HashLength)
+	}
+
+	return nil
+}
+
+func (cfg *ExplorerConfig) Validate() error {
+	if cfg.ApiUrl == "" {
+		return fmt.Errorf("explorer api url is empty")
+	}
+	for name, contract := range cfg.Contracts {
+		if contract.SourceFile == "" {
+			return fmt.Errorf("explorer source file of %s is empty", name)
+		}
+		if contract.CompilerVersion == "" {
+			return fmt.Errorf("explorer compiler version of %s is empty", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import "fmt"
+
+// NetworkConfig is a named EVM network profile. Set fields replace the top level
+// config values when the network is selected.
+type NetworkConfig struct {
+	NodeUrl        string
+	ChainID        int64
+	NativeCurrency string
+	// GasPolicy is the default gas policy of the network
+	GasPolicy      *GasPolicyConfig
+	GravityAddress string
+}
+
+// BuiltinNetworks are the profiles available without a Networks section in the config.
+// A config profile with the same name overrides the built-in values it sets.
+var BuiltinNetworks = map[string]*NetworkConfig{
+	"ethereum": {
+		NodeUrl:        "https://cloudflare-eth.com",
+		ChainID:        1,
+		NativeCurrency: "ETH",
+		GasPolicy: &GasPolicyConfig{
+			TxType: EIP1559TxType,
+		},
+	},
+	"bsc": {
+		NodeUrl:        "https://bsc-dataseed.binance.org",
+		ChainID:        56,
+		NativeCurrency: "BNB",
+		GasPolicy: &GasPolicyConfig{
+			TxType: LegacyTxType,
+		},
+	},
+	"fantom": {
+		NodeUrl:        "https://rpcapi.fantom.network",
+		ChainID:        250,
+		NativeCurrency: "FTM",
+		GasPolicy: &GasPolicyConfig{
+			TxType: LegacyTxType,
+		},
+	},
+	"polygon": {
+		NodeUrl:        "https://polygon-rpc.com",
+		ChainID:        137,
+		NativeCurrency: "MATIC",
+		GasPolicy: &GasPolicyConfig{
+			TxType: EIP1559TxType,
+		},
+	},
+}
+
+// Network returns the named profile, merging the config profile over the built-in one.
+func (cfg *EthereumConfig) Network(name string) (*NetworkConfig, error) {
+	builtin, isBuiltin := BuiltinNetworks[name]
+	custom, isCustom := cfg.Networks[name]
+	if !isBuiltin && !isCustom {
+		return nil, fmt.Errorf("unknown network: %s", name)
+	}
+
+	network := new(NetworkConfig)
+	for _, profile := range []*NetworkConfig{builtin, custom} {
+		if profile == nil {
+			continue
+		}
+		if profile.NodeUrl != "" {
+			network.NodeUrl = profile.NodeUrl
+		}
+		if profile.ChainID != 0 {
+			network.ChainID = profile.ChainID
+		}
+		if profile.NativeCurrency != "" {
+			network.NativeCurrency = profile.NativeCurrency
+		}
+		if profile.GasPolicy != nil {
+			network.GasPolicy = profile.GasPolicy
+		}
+		if profile.GravityAddress != "" {
+			network.GravityAddress = profile.GravityAddress
+		}
+	}
+
+	return network, nil
+}
+
+// ApplyNetwork replaces the node, chain and gravity settings with the named profile.
+func (cfg *EthereumConfig) ApplyNetwork(name string) error {
+	network, err := cfg.Network(name)
+	if err != nil {
+		return err
+	}
+
+	if network.NodeUrl != "" {
+		cfg.NodeUrl = network.NodeUrl
+	}
+	if network.ChainID != 0 {
+		cfg.ChainID = network.ChainID
+	}
+	if network.NativeCurrency != "" {
+		cfg.NativeCurrency = network.NativeCurrency
+	}
+	// The built-in gas policy is a default, a gas policy in the config profile replaces the top level one
+	if custom, ok := cfg.Networks[name]; cfg.GasPolicy == nil || (ok && custom.GasPolicy != nil) {
+		cfg.GasPolicy = network.GasPolicy
+	}
+	if network.GravityAddress != "" {
+		cfg.ExistingGravityAddress = network.GravityAddress
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package deployer
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ParseAmount converts a decimal amount string such as "1.5" into base units
+// of a token with the given number of decimals.
+func ParseAmount(amount string, decimals uint8) (*big.Int, error) {
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return nil, fmt.Errorf("amount is empty")
+	}
+
+	whole, fraction := amount, ""
+	if i := strings.IndexByte(amount, '.'); i >= 0 {
+		whole, fraction = amount[:i], amount[i+1:]
+	}
+	if whole == "" && fraction == "" {
+		return nil, fmt.Errorf("invalid amount: %s", amount)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("amount %s has more than %d decimal places", amount, decimals)
+	}
+	fraction += strings.Repeat("0", int(decimals)-len(fraction))
+
+	value, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok || strings.ContainsAny(whole+fraction, "+-") {
+		return nil, fmt.Errorf("invalid amount: %s", amount)
+	}
+
+	return value, nil
+}
+
+// FormatAmount formats base units of a token with the given number of decimals as a decimal string.
+func FormatAmount(value *big.Int, decimals uint8) string {
+	digits := new(big.Int).Abs(value).String()
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+
+	whole, fraction := digits[:len(digits)-int(decimals)], strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	if value.Sign() < 0 {
+		whole = "-" + whole
+	}
+	if fraction == "" {
+		return whole
+	}
+
+	return whole + "." + fraction
+}
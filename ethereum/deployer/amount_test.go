@@ -0,0 +1,59 @@
+package deployer
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals uint8
+		expected string
+	}{
+		{"1", 18, "1000000000000000000"},
+		{"1.5", 6, "1500000"},
+		{"0.000001", 6, "1"},
+		{".25", 2, "25"},
+		{"42", 0, "42"},
+	}
+
+	for _, test := range tests {
+		value, err := ParseAmount(test.amount, test.decimals)
+		if err != nil {
+			t.Fatalf("parse %s: %v", test.amount, err)
+		}
+		if value.String() != test.expected {
+			t.Errorf("parse %s: expected %s, got %s", test.amount, test.expected, value)
+		}
+	}
+}
+
+func TestParseAmountInvalid(t *testing.T) {
+	for _, amount := range []string{"", ".", "abc", "1.2.3", "-1", "0.0000001"} {
+		if _, err := ParseAmount(amount, 6); err == nil {
+			t.Errorf("parse %q: expected error", amount)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		value    int64
+		decimals uint8
+		expected string
+	}{
+		{1500000, 6, "1.5"},
+		{1, 6, "0.000001"},
+		{42, 0, "42"},
+		{100, 2, "1"},
+		{0, 18, "0"},
+	}
+
+	for _, test := range tests {
+		formatted := FormatAmount(big.NewInt(test.value), test.decimals)
+		if formatted != test.expected {
+			t.Errorf("format %d: expected %s, got %s", test.value, test.expected, formatted)
+		}
+	}
+}
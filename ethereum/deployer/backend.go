@@ -0,0 +1,71 @@
+package deployer
+
+import (
+	"context"
+	"math/big"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// SimulatedChainID is the chain id of the go-ethereum simulated backend.
+	SimulatedChainID  = 1337
+	simulatedGasLimit = 30000000
+)
+
+// Backend is the chain access EthDeployer needs. It is implemented by
+// *ethclient.Client and by SimulatedBackend.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+}
+
+// SimulatedBackend is an in-memory chain for dry runs, every sent transaction is mined immediately.
+type SimulatedBackend struct {
+	*backends.SimulatedBackend
+}
+
+// create2FactoryCode is the runtime code of the deterministic deployment proxy at DefaultCreate2Factory.
+const create2FactoryCode = "0x7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf3"
+
+// NewSimulatedBackend creates an in-memory chain with a funded deployer account
+// and the deterministic deployment proxy, so CREATE2 deployments can be dry run.
+func NewSimulatedBackend(deployer common.Address) *SimulatedBackend {
+	balance := new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)
+	alloc := core.GenesisAlloc{
+		deployer: {Balance: balance},
+		common.HexToAddress(DefaultCreate2Factory): {Balance: new(big.Int), Code: common.FromHex(create2FactoryCode)},
+	}
+
+	return &SimulatedBackend{
+		SimulatedBackend: backends.NewSimulatedBackend(alloc, simulatedGasLimit),
+	}
+}
+
+func (backend *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	err := backend.SimulatedBackend.SendTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	backend.Commit()
+
+	return nil
+}
+
+// TransactionReceipt fails with ethereum.NotFound for unknown transactions like a node,
+// the simulated backend returns neither a receipt nor an error.
+func (backend *SimulatedBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := backend.SimulatedBackend.TransactionReceipt(ctx, txHash)
+	if err == nil && receipt == nil {
+		return nil, ethereum.NotFound
+	}
+
+	return receipt, err
+}
@@ -0,0 +1,206 @@
+package deployer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Bundle is a planned sequence of transactions of one sender, signed offline
+// and broadcast in order.
+type Bundle struct {
+	ChainID      string
+	From         string
+	CreatedAt    time.Time
+	Transactions []*BundleTx
+}
+
+type BundleTx struct {
+	Step string
+	// Contract and Address name the manifest record the transaction belongs to,
+	// for deployments the predicted address of the new contract
+	Contract string
+	Address  string
+	// Deployment is the manifest record of the contract the transaction creates
+	Deployment  *ContractRecord `json:",omitempty"`
+	Transaction *types.Transaction
+	// Signed holds the raw signed transaction
+	Signed hexutil.Bytes `json:",omitempty"`
+}
+
+func (bundle *Bundle) Save(filename string) error {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func LoadBundle(filename string) (*Bundle, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := new(Bundle)
+	if err := json.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("bundle %s: %w", filename, err)
+	}
+
+	return bundle, nil
+}
+
+func (bundle *Bundle) signer() (types.Signer, error) {
+	chainID, ok := new(big.Int).SetString(bundle.ChainID, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid bundle chain id: %s", bundle.ChainID)
+	}
+
+	return types.LatestSignerForChainID(chainID), nil
+}
+
+// Sign signs every transaction of the bundle with the key of the sender. No node is needed.
+func (bundle *Bundle) Sign(key *ecdsa.PrivateKey) error {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	if from != common.HexToAddress(bundle.From) {
+		return fmt.Errorf("bundle is planned for %s, the key belongs to %s", bundle.From, from.Hex())
+	}
+
+	signer, err := bundle.signer()
+	if err != nil {
+		return err
+	}
+
+	for _, planned := range bundle.Transactions {
+		tx, err := types.SignTx(planned.Transaction, signer, key)
+		if err != nil {
+			return fmt.Errorf("sign %s %s: %w", planned.Contract, planned.Step, err)
+		}
+
+		planned.Signed, err = tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// signedTransaction decodes the signed transaction and checks it is the planned one signed by the sender.
+func (planned *BundleTx) signedTransaction(signer types.Signer, from common.Address) (*types.Transaction, error) {
+	if len(planned.Signed) == 0 {
+		return nil, fmt.Errorf("%s %s is not signed", planned.Contract, planned.Step)
+	}
+
+	tx := new(types.Transaction)
+	err := tx.UnmarshalBinary(planned.Signed)
+	if err != nil {
+		return nil, err
+	}
+
+	if signer.Hash(tx) != signer.Hash(planned.Transaction) {
+		return nil, fmt.Errorf("signed %s %s differs from the planned transaction", planned.Contract, planned.Step)
+	}
+
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	if sender != from {
+		return nil, fmt.Errorf("%s %s is signed by %s, expected %s", planned.Contract, planned.Step, sender.Hex(), from.Hex())
+	}
+
+	return tx, nil
+}
+
+// Broadcast sends the signed transactions of the bundle in order and waits for every receipt.
+// Transactions mined or pending from a previous run are not sent again, so an interrupted broadcast can be repeated.
+// Deployed contracts are checked at the predicted addresses and recorded in the manifest.
+func (deployer *EthDeployer) Broadcast(bundle *Bundle, ctx context.Context) error {
+	signer, err := bundle.signer()
+	if err != nil {
+		return err
+	}
+	from := common.HexToAddress(bundle.From)
+	if from != deployer.transactor.From {
+		return fmt.Errorf("bundle is planned for %s, not for %s", bundle.From, deployer.transactor.From.Hex())
+	}
+	// the transactions are recorded in the manifest under their contract
+	for i, planned := range bundle.Transactions {
+		if planned.Contract == "" || !common.IsHexAddress(planned.Address) {
+			return fmt.Errorf("bundle transaction %d %s has no contract, plan the bundle again", i, planned.Step)
+		}
+	}
+
+	for _, planned := range bundle.Transactions {
+		tx, err := planned.signedTransaction(signer, from)
+		if err != nil {
+			return err
+		}
+
+		_, err = deployer.ethClient.TransactionReceipt(ctx, tx.Hash())
+		switch {
+		case err == nil:
+			fmt.Printf("Already mined %s %s: %s \n", planned.Contract, planned.Step, tx.Hash().Hex())
+		case errors.Is(err, ethereum.NotFound):
+			_, _, err = deployer.ethClient.TransactionByHash(ctx, tx.Hash())
+			if err == nil {
+				fmt.Printf("Already sent %s %s, waiting: %s \n", planned.Contract, planned.Step, tx.Hash().Hex())
+				break
+			}
+			if !errors.Is(err, ethereum.NotFound) {
+				return err
+			}
+
+			nonce, err := deployer.ethClient.PendingNonceAt(ctx, from)
+			if err != nil {
+				return err
+			}
+			if tx.Nonce() < nonce {
+				return fmt.Errorf("nonce %d of %s %s is already used, plan the bundle again", tx.Nonce(), planned.Contract, planned.Step)
+			}
+
+			err = deployer.ethClient.SendTransaction(ctx, tx)
+			if err != nil {
+				return fmt.Errorf("send %s %s: %w", planned.Contract, planned.Step, err)
+			}
+
+			fmt.Printf("Sent %s %s: %s \n", planned.Contract, planned.Step, tx.Hash().Hex())
+		default:
+			return err
+		}
+
+		txRecord, err := deployer.waitMined(ctx, planned.Step, tx)
+		if err != nil {
+			return err
+		}
+
+		address := common.HexToAddress(planned.Address)
+		if planned.Deployment != nil {
+			deployed, err := deployer.hasCode(address, ctx)
+			if err != nil {
+				return err
+			}
+			if !deployed {
+				return fmt.Errorf("%s is not deployed at the predicted address %s", planned.Contract, planned.Address)
+			}
+
+			deployer.contracts = append(deployer.contracts, planned.Deployment)
+		}
+		deployer.recordTransaction(planned.Contract, address, txRecord)
+	}
+
+	return nil
+}
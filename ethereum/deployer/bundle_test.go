@@ -0,0 +1,147 @@
+package deployer
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func testBundle(from common.Address) *Bundle {
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	return &Bundle{
+		ChainID: "250",
+		From:    from.Hex(),
+		Transactions: []*BundleTx{
+			{
+				Step:     "subscribe",
+				Contract: "Nebula",
+				Address:  to.Hex(),
+				Transaction: types.NewTx(&types.DynamicFeeTx{
+					Nonce:     3,
+					To:        &to,
+					Gas:       DefaultSubscribeGasLimit,
+					GasFeeCap: big.NewInt(2000000000),
+					GasTipCap: big.NewInt(1000000000),
+					Value:     big.NewInt(0),
+					Data:      []byte{1, 2, 3},
+				}),
+			},
+			{
+				Step:     "transferOwnership",
+				Contract: "Nebula",
+				Address:  to.Hex(),
+				Transaction: types.NewTx(&types.LegacyTx{
+					Nonce:    4,
+					To:       &to,
+					Gas:      100000,
+					GasPrice: big.NewInt(1000000000),
+					Value:    big.NewInt(0),
+				}),
+			},
+		},
+	}
+}
+
+func TestBundleSign(t *testing.T) {
+	keys, addresses := generateKeys(t, 1)
+	path := filepath.Join(t.TempDir(), "bundle.json")
+
+	// The bundle is signed after a round trip through the file, as on the offline host
+	if err := testBundle(addresses[0]).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := LoadBundle(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bundle.Sign(keys[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := bundle.signer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, planned := range bundle.Transactions {
+		tx, err := planned.signedTransaction(signer, addresses[0])
+		if err != nil {
+			t.Fatalf("transaction %d: %v", i, err)
+		}
+		if tx.Nonce() != planned.Transaction.Nonce() {
+			t.Errorf("transaction %d: expected nonce %d, got %d", i, planned.Transaction.Nonce(), tx.Nonce())
+		}
+	}
+
+	// A signature of another planned transaction is rejected
+	bundle.Transactions[0].Signed = bundle.Transactions[1].Signed
+	if _, err := bundle.Transactions[0].signedTransaction(signer, addresses[0]); err == nil {
+		t.Error("expected error for a signature of another transaction")
+	}
+}
+
+func TestBundleSignOtherKey(t *testing.T) {
+	keys, addresses := generateKeys(t, 2)
+
+	if err := testBundle(addresses[0]).Sign(keys[1]); err == nil {
+		t.Error("expected error for a key of another sender")
+	}
+}
+
+func TestBundleBroadcast(t *testing.T) {
+	chain := newSafeChain(t)
+	ctx := context.Background()
+
+	planner, err := NewPlanner(chain.backend, chain.executor, big.NewInt(SimulatedChainID), ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nebula, err := planner.Deployer().DeployNebula(chain.gravity, 2, []common.Address{chain.executor}, 1, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := planner.Bundle()
+	names := []string{"QueueLib", "Nebula"}
+	if len(bundle.Transactions) != len(names) {
+		t.Fatalf("expected %d transactions, got %d", len(names), len(bundle.Transactions))
+	}
+	for i, planned := range bundle.Transactions {
+		address := crypto.CreateAddress(chain.executor, planned.Transaction.Nonce())
+		if planned.Contract != names[i] || planned.Address != address.Hex() || planned.Deployment == nil {
+			t.Errorf("expected the deployment of %s at %s, got %s at %s", names[i], address.Hex(), planned.Contract, planned.Address)
+		}
+	}
+	if bundle.Transactions[1].Address != nebula {
+		t.Errorf("expected the nebula %s, got %s", nebula, bundle.Transactions[1].Address)
+	}
+	for _, record := range planner.Deployer().contracts {
+		if record.Transactions[0].GasUsed != 0 {
+			t.Errorf("expected no gas used by the planned %s, got %d", record.Name, record.Transactions[0].GasUsed)
+		}
+	}
+
+	if err := bundle.Sign(chain.key); err != nil {
+		t.Fatal(err)
+	}
+	ethDeployer := NewEthDeployer(chain.backend, chain.transactor(t))
+	if err := ethDeployer.Broadcast(bundle, ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := ethDeployer.Manifest(big.NewInt(SimulatedChainID))
+	if len(manifest.Contracts) != len(names) {
+		t.Fatalf("expected %d manifest records, got %d", len(names), len(manifest.Contracts))
+	}
+	for i, record := range manifest.Contracts {
+		if record.Name != names[i] || len(record.Transactions) != 1 || record.Transactions[0].GasUsed == 0 {
+			t.Errorf("unexpected manifest record %+v", record)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GravityInfo is the current state of a gravity contract.
+type GravityInfo struct {
+	Round          *big.Int
+	BftCoefficient *big.Int
+	Consuls        []common.Address
+}
+
+// ReadGravity reads the current round, bft coefficient and consuls of the gravity contract.
+func ReadGravity(backend Backend, gravityAddress common.Address) (*GravityInfo, error) {
+	gravity, err := ethereum.NewGravity(gravityAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	round, err := gravity.LastRound(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bftValue, err := gravity.BftValue(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	consuls, err := gravity.GetConsuls(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GravityInfo{
+		Round:          round,
+		BftCoefficient: bftValue,
+		Consuls:        consuls,
+	}, nil
+}
+
+// ConsulsUpdate is a new consul set of gravity with the signatures of the current consuls.
+type ConsulsUpdate struct {
+	GravityAddress common.Address
+	Consuls        []common.Address
+	Round          *big.Int
+	Signatures     *ConsulSignatures
+	// Quorum is the bft coefficient of gravity
+	Quorum int
+}
+
+// NewConsulsUpdate checks the round is after the current one and assigns the signatures to the current consuls.
+// The message hash is checked against the one computed by the contract.
+func NewConsulsUpdate(backend Backend, gravityAddress common.Address, consuls []common.Address, round *big.Int, signatures [][]byte) (*ConsulsUpdate, error) {
+	gravity, err := ethereum.NewGravity(gravityAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := ReadGravity(backend, gravityAddress)
+	if err != nil {
+		return nil, err
+	}
+	if round.Cmp(info.Round) <= 0 {
+		return nil, fmt.Errorf("round %s must be after the current round %s", round, info.Round)
+	}
+
+	hash := UpdateMessageHash(consuls, round)
+	contractHash, err := gravity.HashNewConsuls(nil, consuls, round)
+	if err != nil {
+		return nil, err
+	}
+	if common.Hash(contractHash) != hash {
+		return nil, fmt.Errorf("message hash %s differs from the gravity hash %s", hash.Hex(), common.Hash(contractHash).Hex())
+	}
+
+	assigned, err := AssignSignatures(hash, info.Consuls, signatures)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsulsUpdate{
+		GravityAddress: gravityAddress,
+		Consuls:        consuls,
+		Round:          round,
+		Signatures:     assigned,
+		Quorum:         int(info.BftCoefficient.Int64()),
+	}, nil
+}
+
+func (update *ConsulsUpdate) Approvals() (*ConsulSignatures, int) {
+	return update.Signatures, update.Quorum
+}
+
+// CheckQuorum fails when fewer consuls signed the update than the quorum.
+func (update *ConsulsUpdate) CheckQuorum() error {
+	return update.Signatures.checkQuorum(update.Quorum)
+}
+
+// UpdateConsuls submits the consul update once the quorum is reached and confirms the new round and consuls on-chain.
+func (deployer *EthDeployer) UpdateConsuls(update *ConsulsUpdate, ctx context.Context) (string, error) {
+	err := update.CheckQuorum()
+	if err != nil {
+		return "", err
+	}
+
+	gravity, err := ethereum.NewGravity(update.GravityAddress, deployer.ethClient)
+	if err != nil {
+		return "", err
+	}
+
+	opts, err := deployer.opts(ctx, "updateConsuls")
+	if err != nil {
+		return "", err
+	}
+
+	signatures := update.Signatures
+	tx, err := gravity.UpdateConsuls(opts, update.Consuls, signatures.V, signatures.R, signatures.S, update.Round)
+	if err != nil {
+		return "", err
+	}
+
+	txRecord, err := deployer.waitMined(ctx, "updateConsuls", tx)
+	if err != nil {
+		return "", err
+	}
+	deployer.recordTransaction("Gravity", update.GravityAddress, txRecord)
+	if deployer.planning() {
+		return tx.Hash().Hex(), nil
+	}
+
+	info, err := ReadGravity(deployer.ethClient, update.GravityAddress)
+	if err != nil {
+		return "", err
+	}
+	if info.Round.Cmp(update.Round) != 0 {
+		return "", fmt.Errorf("gravity %s is at round %s after the update, expected %s", update.GravityAddress.Hex(), info.Round, update.Round)
+	}
+	if formatAddresses(info.Consuls) != formatAddresses(update.Consuls) {
+		return "", fmt.Errorf("gravity %s has consuls %s after the update, expected %s",
+			update.GravityAddress.Hex(), formatAddresses(info.Consuls), formatAddresses(update.Consuls))
+	}
+
+	return tx.Hash().Hex(), nil
+}
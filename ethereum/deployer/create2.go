@@ -0,0 +1,318 @@
+package deployer
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Gravity-Tech/gateway/abi/ethereum/ibport"
+	"github.com/Gravity-Tech/gateway/abi/ethereum/luport"
+	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultCreate2Factory is the deterministic deployment proxy available on most EVM chains.
+// It takes the salt followed by the init code as calldata.
+const DefaultCreate2Factory = "0x4e59b44847b379578588920cA78FbF26c0B4956C"
+
+// Create2 deploys contracts through a CREATE2 factory, so the same salt and
+// constructor arguments give the same address on every chain.
+// Contracts that take their owner from msg.sender would be owned by the factory, so they are refused.
+type Create2 struct {
+	Factory common.Address
+	Salt    common.Hash
+	// Port is the name of the port the contracts are deployed for, so ports with
+	// the same constructor arguments get distinct addresses
+	Port string
+}
+
+func (deployer *EthDeployer) SetCreate2(create2 *Create2) {
+	deployer.create2 = create2
+}
+
+// SetCreate2Port derives the salts of the following CREATE2 deployments from the port name.
+func (deployer *EthDeployer) SetCreate2Port(port string) {
+	if deployer.create2 == nil {
+		return
+	}
+
+	deployer.create2 = deployer.create2.ForPort(port)
+}
+
+// ForPort returns the CREATE2 deployment of the named port.
+func (create2 *Create2) ForPort(port string) *Create2 {
+	return &Create2{
+		Factory: create2.Factory,
+		Salt:    create2.Salt,
+		Port:    port,
+	}
+}
+
+// contractSalt derives a distinct salt for every contract of every port from the configured salt.
+func (create2 *Create2) contractSalt(name string) common.Hash {
+	if create2.Port == "" {
+		return crypto.Keccak256Hash(create2.Salt.Bytes(), []byte(name))
+	}
+
+	return crypto.Keccak256Hash(create2.Salt.Bytes(), crypto.Keccak256([]byte(create2.Port)), []byte(name))
+}
+
+// Address predicts the address of a contract deployed with the init code.
+func (create2 *Create2) Address(name string, initCode []byte) common.Address {
+	return crypto.CreateAddress2(create2.Factory, create2.contractSalt(name), crypto.Keccak256(initCode))
+}
+
+// PredictQueueLibAddress returns the address of the QueueLib library the CREATE2 deployment links the nebula to.
+func (create2 *Create2) PredictQueueLibAddress() common.Address {
+	return create2.Address("QueueLib", common.FromHex(ethereum.QueueLibBin))
+}
+
+// PredictNebulaAddress returns the nebula address the CREATE2 deployment will use.
+func (create2 *Create2) PredictNebulaAddress(gravityAddress string, dataType int, oracles []common.Address, bftCoefficient int) (common.Address, error) {
+	nebulaCode, err := nebulaInitCode(create2.PredictQueueLibAddress(), gravityAddress, dataType, oracles, bftCoefficient)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return create2.Address("Nebula", nebulaCode), nil
+}
+
+// PredictPortAddresses returns the nebula and port addresses the CREATE2 deployment will use.
+func (create2 *Create2) PredictPortAddresses(gravityAddress string, dataType int, existingToken string,
+	oracles []common.Address, bftCoefficient int, portType PortType) (common.Address, common.Address, error) {
+
+	nebulaAddress, err := create2.PredictNebulaAddress(gravityAddress, dataType, oracles, bftCoefficient)
+	if err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+
+	portCode, err := portInitCode(portType, nebulaAddress, common.HexToAddress(existingToken))
+	if err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+
+	return nebulaAddress, create2.Address(portType.ContractName(), portCode), nil
+}
+
+// packConstructor ABI encodes the constructor arguments.
+func packConstructor(contractAbi string, args ...interface{}) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractAbi))
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.Pack("", args...)
+}
+
+func nebulaConstructor(gravityAddress string, dataType int, oracles []common.Address, bftCoefficient int) ([]byte, error) {
+	return packConstructor(ethereum.NebulaABI,
+		uint8(dataType), common.HexToAddress(gravityAddress), oracles, big.NewInt(int64(bftCoefficient)))
+}
+
+func portConstructor(portType PortType, nebulaAddress common.Address, tokenAddress common.Address) ([]byte, error) {
+	switch portType {
+	case IBPort:
+		return packConstructor(ibport.IBPortABI, nebulaAddress, tokenAddress)
+	case LUPort:
+		return packConstructor(luport.LUPortABI, nebulaAddress, tokenAddress)
+	}
+
+	return nil, fmt.Errorf("unknown port type %d", portType)
+}
+
+// queueLibPlaceholder marks the address of the QueueLib library in the nebula code.
+const queueLibPlaceholder = "__$0a43b38f5af48986f17d40dabc0635caed$__"
+
+// nebulaCode links a copy of the nebula code to the QueueLib library. DeployNebula of the
+// binding would deploy a library of its own and link the shared NebulaBin to it for good.
+func nebulaCode(queueLibAddress common.Address) []byte {
+	return common.FromHex(strings.Replace(ethereum.NebulaBin, queueLibPlaceholder, hex.EncodeToString(queueLibAddress.Bytes()), -1))
+}
+
+// constructorHex formats encoded constructor arguments for the manifest.
+func constructorHex(data []byte, err error) string {
+	if err != nil {
+		return ""
+	}
+
+	return hexutil.Encode(data)
+}
+
+func nebulaInitCode(queueLibAddress common.Address, gravityAddress string, dataType int,
+	oracles []common.Address, bftCoefficient int) ([]byte, error) {
+
+	constructor, err := nebulaConstructor(gravityAddress, dataType, oracles, bftCoefficient)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(nebulaCode(queueLibAddress), constructor...), nil
+}
+
+func portInitCode(portType PortType, nebulaAddress common.Address, tokenAddress common.Address) ([]byte, error) {
+	constructor, err := portConstructor(portType, nebulaAddress, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	var bin string
+	switch portType {
+	case IBPort:
+		bin = ibport.IBPortBin
+	case LUPort:
+		bin = luport.LUPortBin
+	}
+
+	return append(common.FromHex(bin), constructor...), nil
+}
+
+// checkOwner creates the contract in a local chain on behalf of the factory and refuses it
+// when it is owned by the factory, as nobody could administer it afterwards.
+func (create2 *Create2) checkOwner(name string, initCode []byte) error {
+	chain, err := newLocalChain()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = chain.execute(create2.Factory, 0, nil, nil, initCode, true)
+	if err != nil {
+		return fmt.Errorf("check the owner of %s: %w", name, err)
+	}
+	address := crypto.CreateAddress(create2.Factory, 0)
+
+	parsed, err := abi.JSON(strings.NewReader(ownableABI))
+	if err != nil {
+		return err
+	}
+	data, err := parsed.Pack("owner")
+	if err != nil {
+		return err
+	}
+
+	// a contract without owner reverts or returns nothing
+	out, _, err := chain.execute(common.Address{}, 0, &address, nil, data, false)
+	if err != nil || len(out) < common.HashLength {
+		return nil
+	}
+	if common.BytesToAddress(out[:common.HashLength]) == create2.Factory {
+		return fmt.Errorf("%s would be owned by the CREATE2 factory %s, deploy it without CREATE2", name, create2.Factory.Hex())
+	}
+
+	return nil
+}
+
+// deployCreate2 sends the init code to the factory. The transaction is nil
+// when the contract already exists at the predicted address.
+func (deployer *EthDeployer) deployCreate2(ctx context.Context, opts *bind.TransactOpts, name string, initCode []byte) (common.Address, *types.Transaction, error) {
+	err := deployer.create2.checkOwner(name, initCode)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	address := deployer.create2.Address(name, initCode)
+
+	deployed, err := deployer.hasCode(address, ctx)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	if deployed {
+		fmt.Printf("%s already deployed by CREATE2: %s \n", name, address.Hex())
+		return address, nil, nil
+	}
+
+	factoryDeployed, err := deployer.hasCode(deployer.create2.Factory, ctx)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	if !factoryDeployed {
+		return common.Address{}, nil, fmt.Errorf("CREATE2 factory %s is not deployed", deployer.create2.Factory.Hex())
+	}
+
+	salt := deployer.create2.contractSalt(name)
+	factory := bind.NewBoundContract(deployer.create2.Factory, abi.ABI{}, deployer.ethClient, deployer.ethClient, deployer.ethClient)
+	tx, err := factory.RawTransact(opts, append(salt.Bytes(), initCode...))
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	return address, tx, nil
+}
+
+// deployQueueLib deploys the QueueLib library the nebula is linked to directly or through the CREATE2 factory.
+func (deployer *EthDeployer) deployQueueLib(ctx context.Context, opts *bind.TransactOpts) (common.Address, *types.Transaction, error) {
+	if deployer.create2 != nil {
+		return deployer.deployCreate2(ctx, opts, "QueueLib", common.FromHex(ethereum.QueueLibBin))
+	}
+
+	queueLibAddress, tx, _, err := ethereum.DeployQueueLib(opts, deployer.ethClient)
+
+	return queueLibAddress, tx, err
+}
+
+// deployNebula deploys the nebula linked to the QueueLib library directly or through the CREATE2 factory.
+func (deployer *EthDeployer) deployNebula(ctx context.Context, opts *bind.TransactOpts, queueLibAddress common.Address,
+	gravityAddress string, dataType int, oracles []common.Address, bftCoefficient int) (common.Address, *types.Transaction, error) {
+
+	if deployer.create2 != nil {
+		code, err := nebulaInitCode(queueLibAddress, gravityAddress, dataType, oracles, bftCoefficient)
+		if err != nil {
+			return common.Address{}, nil, err
+		}
+
+		return deployer.deployCreate2(ctx, opts, "Nebula", code)
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(ethereum.NebulaABI))
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	nebulaAddress, tx, _, err := bind.DeployContract(
+		opts,
+		parsed,
+		nebulaCode(queueLibAddress),
+		deployer.ethClient,
+		uint8(dataType),
+		common.HexToAddress(gravityAddress),
+		oracles,
+		big.NewInt(int64(bftCoefficient)),
+	)
+
+	return nebulaAddress, tx, err
+}
+
+// deployPortContract deploys the port directly or through the CREATE2 factory.
+func (deployer *EthDeployer) deployPortContract(ctx context.Context, opts *bind.TransactOpts, portType PortType,
+	nebulaAddress common.Address, tokenAddress common.Address) (common.Address, *types.Transaction, error) {
+
+	if deployer.create2 != nil {
+		code, err := portInitCode(portType, nebulaAddress, tokenAddress)
+		if err != nil {
+			return common.Address{}, nil, err
+		}
+
+		return deployer.deployCreate2(ctx, opts, portType.ContractName(), code)
+	}
+
+	var portAddress common.Address
+	var tx *types.Transaction
+	var err error
+	switch portType {
+	case IBPort:
+		portAddress, tx, _, err = ibport.DeployIBPort(opts, deployer.ethClient, nebulaAddress, tokenAddress)
+	case LUPort:
+		portAddress, tx, _, err = luport.DeployLUPort(opts, deployer.ethClient, nebulaAddress, tokenAddress)
+	default:
+		err = fmt.Errorf("unknown port type %d", portType)
+	}
+
+	return portAddress, tx, err
+}
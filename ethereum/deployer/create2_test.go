@@ -0,0 +1,94 @@
+package deployer
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ownerCode answers every call with the owner stored by the constructor.
+var ownerCode = assemble(push1(0), vm.SLOAD, push1(0), vm.MSTORE, push1(32), push1(0), vm.RETURN)
+
+// initCode deploys ownerCode after running the constructor, which has to be 4 bytes long.
+func initCode(constructor ...interface{}) []byte {
+	items := append(constructor,
+		push1(byte(len(ownerCode))), push1(16), push1(0), vm.CODECOPY,
+		push1(byte(len(ownerCode))), push1(0), vm.RETURN,
+	)
+
+	return append(assemble(items...), ownerCode...)
+}
+
+func TestCreate2CheckOwner(t *testing.T) {
+	create2 := &Create2{Factory: common.HexToAddress(DefaultCreate2Factory)}
+
+	// the owner is msg.sender, so the factory
+	err := create2.checkOwner("Ownable", initCode(vm.CALLER, push1(0), vm.SSTORE))
+	if err == nil || !strings.Contains(err.Error(), "owned by the CREATE2 factory") {
+		t.Errorf("expected an error for a contract owned by the factory, got %v", err)
+	}
+
+	// the owner is the zero address, as after renounceOwnership
+	err = create2.checkOwner("Renounced", initCode(vm.JUMPDEST, vm.JUMPDEST, vm.JUMPDEST, vm.JUMPDEST))
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = create2.checkOwner("NotOwnable", assemble(vm.STOP))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreate2Nebula(t *testing.T) {
+	keys, addresses := generateKeys(t, 1)
+	transactor, err := bind.NewKeyedTransactorWithChainID(keys[0], big.NewInt(SimulatedChainID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := NewSimulatedBackend(transactor.From)
+	defer backend.Close()
+	ctx := context.Background()
+
+	ethDeployer := NewEthDeployer(backend, transactor)
+	gravity, err := ethDeployer.DeployGravity([]string{addresses[0].Hex()}, 1, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create2 := &Create2{Factory: common.HexToAddress(DefaultCreate2Factory), Salt: common.HexToHash("0x01"), Port: "test"}
+	ethDeployer.SetCreate2(create2)
+
+	oracles := []common.Address{addresses[0]}
+	predicted, err := create2.PredictNebulaAddress(gravity, 2, oracles, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := new(PortState)
+	nebula, err := ethDeployer.DeployNebula(gravity, 2, oracles, 1, state, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.HexToAddress(nebula) != predicted {
+		t.Errorf("expected the nebula at the predicted address %s, got %s", predicted.Hex(), nebula)
+	}
+
+	queueLib := state.contract("QueueLib")
+	if queueLib == nil || common.HexToAddress(queueLib.Address) != create2.PredictQueueLibAddress() {
+		t.Errorf("expected QueueLib at the predicted address %s, got %+v", create2.PredictQueueLibAddress().Hex(), queueLib)
+	}
+
+	code, err := backend.CodeAt(ctx, predicted, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) == 0 {
+		t.Fatalf("nebula is not created at the predicted address %s", predicted.Hex())
+	}
+}
@@ -4,15 +4,14 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"time"
 
-	"github.com/Gravity-Tech/gateway/abi/ethereum/ibport"
 	erc20 "github.com/Gravity-Tech/gateway/abi/ethereum/erc20"
-	"github.com/Gravity-Tech/gateway/abi/ethereum/luport"
 	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 const (
@@ -33,6 +32,21 @@ func (t PortType) Format() string {
 	return ""
 }
 
+// ContractName returns the name of the port contract.
+func (t PortType) ContractName() string {
+	switch t {
+	case LUPort:
+		return "LUPort"
+	case IBPort:
+		return "IBPort"
+	}
+
+	return ""
+}
+
+// PortDataType is the nebula data type of the ports, both read the transfer requests from bytes.
+const PortDataType = BytesType
+
 type GatewayPort struct {
 	PortAddress   string
 	NebulaAddress string
@@ -40,64 +54,183 @@ type GatewayPort struct {
 }
 
 type EthDeployer struct {
-	ethClient  *ethclient.Client
+	ethClient  Backend
 	transactor *bind.TransactOpts
+	gasPolicy  *GasPolicy
+	create2    *Create2
+	contracts  []*ContractRecord
+	// mined holds every transaction mined by this deployer, recorded in the manifest or not
+	mined []*TxRecord
 }
 
-func NewEthDeployer(ethClient *ethclient.Client, transactor *bind.TransactOpts) *EthDeployer {
+func NewEthDeployer(ethClient Backend, transactor *bind.TransactOpts) *EthDeployer {
 	return &EthDeployer{
 		ethClient:  ethClient,
 		transactor: transactor,
 	}
 }
 
-func (deployer *EthDeployer) DeployPort(gravityAddress string, dataType int, existingToken string,
-	oracles []common.Address, bftCoefficient int, portType PortType, ctx context.Context) (*GatewayPort, error) {
+// Manifest returns the record of every contract deployed by this deployer so far.
+func (deployer *EthDeployer) Manifest(chainID *big.Int) *Manifest {
+	return &Manifest{
+		ChainID:   chainID.String(),
+		Deployer:  deployer.transactor.From.Hex(),
+		CreatedAt: time.Now().UTC(),
+		Contracts: deployer.contracts,
+	}
+}
 
-	erc20Address := common.HexToAddress(existingToken)
+// GasUsed returns the gas used by every transaction mined by this deployer so far.
+func (deployer *EthDeployer) GasUsed() uint64 {
+	var gasUsed uint64
+	for _, txRecord := range deployer.mined {
+		gasUsed += txRecord.GasUsed
+	}
 
-	fmt.Printf("ERC20: %v \n", erc20Address.String())
+	return gasUsed
+}
+
+func (deployer *EthDeployer) recordContract(name string, address common.Address, args map[string]interface{}) *ContractRecord {
+	record := &ContractRecord{
+		Name:            name,
+		Address:         address.Hex(),
+		ConstructorArgs: args,
+	}
+	deployer.contracts = append(deployer.contracts, record)
+
+	return record
+}
 
-	nebulaAddress, tx, nebula, err := ethereum.DeployNebula(
-		deployer.transactor,
-		deployer.ethClient,
-		uint8(dataType),
-		common.HexToAddress(gravityAddress),
-		oracles,
-		big.NewInt(int64(bftCoefficient)),
-	)
+// recordTransaction adds the transaction to the manifest record of the contract. Contracts
+// not deployed or resumed in this run get a record without constructor arguments.
+func (deployer *EthDeployer) recordTransaction(name string, address common.Address, txRecord *TxRecord) {
+	record := deployer.contractRecord(address)
+	if record == nil {
+		record = deployer.recordContract(name, address, nil)
+	}
+	record.Transactions = append(record.Transactions, txRecord)
+}
 
+// planning checks whether the transactions are only recorded for a plan, see Planner.
+// Nothing can be confirmed on-chain for planned transactions.
+func (deployer *EthDeployer) planning() bool {
+	_, ok := deployer.ethClient.(*planBackend)
+	return ok
+}
+
+// waitMined waits for the transaction receipt and fails on reverted transactions.
+func (deployer *EthDeployer) waitMined(ctx context.Context, step string, tx *types.Transaction) (*TxRecord, error) {
+	receipt, err := bind.WaitMined(ctx, deployer.ethClient, tx)
 	if err != nil {
 		return nil, err
 	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("%s transaction %s failed", step, tx.Hash().Hex())
+	}
 
-	_, err = bind.WaitMined(ctx, deployer.ethClient, tx)
+	gasPrice, err := deployer.paidGasPrice(ctx, tx, receipt)
 	if err != nil {
 		return nil, err
 	}
 
-	var portAddress common.Address
-	switch portType {
-	case IBPort:
-		portAddress, tx, _, err = ibport.DeployIBPort(deployer.transactor, deployer.ethClient, nebulaAddress, erc20Address)
-	case LUPort:
-		portAddress, tx, _, err = luport.DeployLUPort(deployer.transactor, deployer.ethClient, nebulaAddress, erc20Address)
+	txRecord := &TxRecord{
+		Step:        step,
+		TxHash:      tx.Hash().Hex(),
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		GasUsed:     receipt.GasUsed,
+		Fee:         new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(receipt.GasUsed)).String(),
+	}
+	deployer.mined = append(deployer.mined, txRecord)
+
+	return txRecord, nil
+}
+
+// paidGasPrice returns the gas price paid by a mined transaction. EIP-1559 transactions
+// pay the base fee of their block plus the tip, at most the fee cap.
+func (deployer *EthDeployer) paidGasPrice(ctx context.Context, tx *types.Transaction, receipt *types.Receipt) (*big.Int, error) {
+	if tx.Type() == types.LegacyTxType || tx.Type() == types.AccessListTxType {
+		return tx.GasPrice(), nil
 	}
+
+	header, err := deployer.ethClient.HeaderByNumber(ctx, receipt.BlockNumber)
 	if err != nil {
 		return nil, err
 	}
+	if header.BaseFee == nil {
+		return tx.GasFeeCap(), nil
+	}
+
+	return capValue(new(big.Int).Add(header.BaseFee, tx.GasTipCap()), tx.GasFeeCap()), nil
+}
+
+// DeployPort deploys the nebula and the port and subscribes the port to the nebula.
+// Steps completed in a previous run are taken from the state and checked on-chain.
+func (deployer *EthDeployer) DeployPort(gravityAddress string, dataType int, existingToken string,
+	oracles []common.Address, bftCoefficient int, portType PortType, subscription *Subscription, state *PortState, ctx context.Context) (*GatewayPort, error) {
+
+	if state == nil {
+		state = new(PortState)
+	}
+	if subscription == nil {
+		subscription = DefaultSubscription()
+	}
 
-	_, err = bind.WaitMined(ctx, deployer.ethClient, tx)
+	erc20Address := common.HexToAddress(existingToken)
+
+	fmt.Printf("ERC20: %v \n", erc20Address.String())
+
+	nebulaRecord, err := deployer.deployNebulaStep(gravityAddress, dataType, oracles, bftCoefficient, state, ctx)
+	if err != nil {
+		return nil, err
+	}
+	nebulaRecord.PortType = portType.Format()
+	nebulaAddress := common.HexToAddress(nebulaRecord.Address)
+
+	portRecord, err := deployer.resumePort(state, portType, nebulaAddress, erc20Address, ctx)
 	if err != nil {
 		return nil, err
 	}
+	if portRecord == nil {
+		opts, err := deployer.opts(ctx, portType.ContractName())
+		if err != nil {
+			return nil, err
+		}
+
+		portAddress, tx, err := deployer.deployPortContract(ctx, opts, portType, nebulaAddress, erc20Address)
+		if err != nil {
+			return nil, err
+		}
+
+		var txRecord *TxRecord
+		if tx != nil {
+			txRecord, err = deployer.waitMined(ctx, "deploy", tx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		portRecord = deployer.recordContract(portType.ContractName(), portAddress, portArgs(nebulaAddress, erc20Address))
+		portRecord.PortType = portType.Format()
+		portRecord.ConstructorData = constructorHex(portConstructor(portType, nebulaAddress, erc20Address))
+		if txRecord != nil {
+			portRecord.Transactions = append(portRecord.Transactions, txRecord)
+		}
+
+		err = state.complete(portRecord)
+		if err != nil {
+			return nil, err
+		}
+	}
+	portAddress := common.HexToAddress(portRecord.Address)
 
-	tx, err = nebula.Subscribe(deployer.transactor, portAddress, 1, big.NewInt(0))
+	// Subscribe skips a subscription with the same parameters and fails on one with other parameters
+	_, err = deployer.Subscribe(nebulaAddress, portAddress, subscription.MinConfirmations, subscription.Reward, ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = bind.WaitMined(ctx, deployer.ethClient, tx)
+	state.Subscribed = true
+	err = state.Save()
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +242,100 @@ func (deployer *EthDeployer) DeployPort(gravityAddress string, dataType int, exi
 	}, nil
 }
 
-func (deployer *EthDeployer) Faucet(erc20Address string, receiver string, amount int64, ctx context.Context) (string, error) {
+// DeployNebula deploys a nebula without a port, for subscribers such as price feeds.
+// A nebula completed in a previous run is taken from the state.
+func (deployer *EthDeployer) DeployNebula(gravityAddress string, dataType int, oracles []common.Address,
+	bftCoefficient int, state *PortState, ctx context.Context) (string, error) {
+
+	if state == nil {
+		state = new(PortState)
+	}
+
+	nebulaRecord, err := deployer.deployNebulaStep(gravityAddress, dataType, oracles, bftCoefficient, state, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return nebulaRecord.Address, nil
+}
+
+// deployNebulaStep deploys the nebula unless the state holds one from a previous run.
+func (deployer *EthDeployer) deployNebulaStep(gravityAddress string, dataType int, oracles []common.Address,
+	bftCoefficient int, state *PortState, ctx context.Context) (*ContractRecord, error) {
+
+	nebulaRecord, err := deployer.resumeNebula(state, common.HexToAddress(gravityAddress), dataType, oracles, bftCoefficient, ctx)
+	if err != nil || nebulaRecord != nil {
+		return nebulaRecord, err
+	}
+
+	queueLibRecord, err := deployer.deployQueueLibStep(state, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := deployer.opts(ctx, "Nebula")
+	if err != nil {
+		return nil, err
+	}
+
+	nebulaAddress, tx, err := deployer.deployNebula(ctx, opts, common.HexToAddress(queueLibRecord.Address),
+		gravityAddress, dataType, oracles, bftCoefficient)
+	if err != nil {
+		return nil, err
+	}
+
+	var txRecord *TxRecord
+	if tx != nil {
+		txRecord, err = deployer.waitMined(ctx, "deploy", tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nebulaRecord = deployer.recordContract("Nebula", nebulaAddress,
+		nebulaArgs(common.HexToAddress(gravityAddress), dataType, oracles, bftCoefficient))
+	nebulaRecord.ConstructorData = constructorHex(nebulaConstructor(gravityAddress, dataType, oracles, bftCoefficient))
+	if txRecord != nil {
+		nebulaRecord.Transactions = append(nebulaRecord.Transactions, txRecord)
+	}
+
+	return nebulaRecord, state.complete(nebulaRecord)
+}
+
+// deployQueueLibStep deploys the QueueLib library of the nebula unless the state holds one from a previous run.
+func (deployer *EthDeployer) deployQueueLibStep(state *PortState, ctx context.Context) (*ContractRecord, error) {
+	queueLibRecord, err := deployer.resumeContract(state, "QueueLib", nil, ctx)
+	if err != nil || queueLibRecord != nil {
+		return queueLibRecord, err
+	}
+
+	opts, err := deployer.opts(ctx, "QueueLib")
+	if err != nil {
+		return nil, err
+	}
+
+	queueLibAddress, tx, err := deployer.deployQueueLib(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var txRecord *TxRecord
+	if tx != nil {
+		txRecord, err = deployer.waitMined(ctx, "deploy", tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	queueLibRecord = deployer.recordContract("QueueLib", queueLibAddress, nil)
+	if txRecord != nil {
+		queueLibRecord.Transactions = append(queueLibRecord.Transactions, txRecord)
+	}
+
+	return queueLibRecord, state.complete(queueLibRecord)
+}
+
+func (deployer *EthDeployer) Faucet(erc20Address string, receiver string, amount string, ctx context.Context) (string, error) {
 	erc20Token, err := erc20.NewToken(common.HexToAddress(erc20Address), deployer.ethClient)
 	if err != nil {
 		return "", err
@@ -120,14 +346,25 @@ func (deployer *EthDeployer) Faucet(erc20Address string, receiver string, amount
 		return "", err
 	}
 
-	value := big.NewInt(int64(amount))
-	value.Mul(value, big.NewInt(0).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
-	tx, err := erc20Token.Mint(deployer.transactor, common.HexToAddress(receiver), value)
+	value, err := ParseAmount(amount, decimals)
+	if err != nil {
+		return "", err
+	}
+	if value.Sign() == 0 {
+		return "", fmt.Errorf("amount %s is zero", amount)
+	}
+
+	opts, err := deployer.opts(ctx, "mint")
 	if err != nil {
 		return "", err
 	}
 
-	_, err = bind.WaitMined(ctx, deployer.ethClient, tx)
+	tx, err := erc20Token.Mint(opts, common.HexToAddress(receiver), value)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = deployer.waitMined(ctx, "mint", tx)
 	if err != nil {
 		return "", err
 	}
@@ -135,22 +372,127 @@ func (deployer *EthDeployer) Faucet(erc20Address string, receiver string, amount
 	return tx.Hash().Hex(), nil
 }
 
-func (deployer *EthDeployer) DeployGravity(consuls [5]string, bftCoefficient int, ctx context.Context) (string, error) {
+// DeployTestToken deploys a mintable ERC20 token and mints the initial supply to the deployer.
+// The supply is parsed with the decimals of the token, which fails when they differ from non-zero decimals.
+// A token or mint completed in a previous run is taken from the state.
+func (deployer *EthDeployer) DeployTestToken(name string, symbol string, decimals uint8, initialSupply string, state *PortState, ctx context.Context) (string, error) {
+	if state == nil {
+		state = new(PortState)
+	}
+
+	args := map[string]interface{}{
+		"name":   name,
+		"symbol": symbol,
+	}
+	tokenRecord, err := deployer.resumeContract(state, "Token", args, ctx)
+	if err != nil {
+		return "", err
+	}
+	if tokenRecord == nil {
+		opts, err := deployer.opts(ctx, "Token")
+		if err != nil {
+			return "", err
+		}
+
+		tokenAddress, tx, _, err := erc20.DeployToken(opts, deployer.ethClient, name, symbol)
+		if err != nil {
+			return "", err
+		}
+
+		txRecord, err := deployer.waitMined(ctx, "deploy", tx)
+		if err != nil {
+			return "", err
+		}
+
+		tokenRecord = deployer.recordContract("Token", tokenAddress, args)
+		tokenRecord.ConstructorData = constructorHex(packConstructor(erc20.TokenABI, name, symbol))
+		tokenRecord.Transactions = append(tokenRecord.Transactions, txRecord)
+
+		// the token is saved before the mint, so a rerun after a failed mint only mints
+		err = state.complete(tokenRecord)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if initialSupply == "" || state.Minted {
+		return tokenRecord.Address, nil
+	}
+
+	token, err := erc20.NewToken(common.HexToAddress(tokenRecord.Address), deployer.ethClient)
+	if err != nil {
+		return "", err
+	}
+
+	tokenDecimals, err := token.Decimals(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return "", err
+	}
+	if decimals != 0 && decimals != tokenDecimals {
+		return "", fmt.Errorf("test token %s has %d decimals, config expects %d", tokenRecord.Address, tokenDecimals, decimals)
+	}
+
+	value, err := ParseAmount(initialSupply, tokenDecimals)
+	if err != nil {
+		return "", err
+	}
+
+	opts, err := deployer.opts(ctx, "mint")
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := token.Mint(opts, deployer.transactor.From, value)
+	if err != nil {
+		return "", err
+	}
+
+	txRecord, err := deployer.waitMined(ctx, "mint", tx)
+	if err != nil {
+		return "", err
+	}
+	tokenRecord.Transactions = append(tokenRecord.Transactions, txRecord)
+
+	state.Minted = true
+
+	return tokenRecord.Address, state.Save()
+}
+
+func (deployer *EthDeployer) DeployGravity(consuls []string, bftCoefficient int, ctx context.Context) (string, error) {
+	if bftCoefficient <= 0 {
+		return "", fmt.Errorf("bft coefficient cannot be less than 1")
+	}
+	if bftCoefficient > len(consuls) {
+		return "", fmt.Errorf("bft coefficient %d is unreachable with %d consuls", bftCoefficient, len(consuls))
+	}
+
 	var consulsAddress []common.Address
 
 	for _, v := range consuls {
 		consulsAddress = append(consulsAddress, common.HexToAddress(v))
 	}
 
-	gravityAddress, tx, _, err := ethereum.DeployGravity(deployer.transactor, deployer.ethClient, consulsAddress[:], big.NewInt(int64(bftCoefficient)))
+	opts, err := deployer.opts(ctx, "Gravity")
 	if err != nil {
 		return "", err
 	}
 
-	_, err = bind.WaitMined(ctx, deployer.ethClient, tx)
+	gravityAddress, tx, _, err := ethereum.DeployGravity(opts, deployer.ethClient, consulsAddress, big.NewInt(int64(bftCoefficient)))
 	if err != nil {
 		return "", err
 	}
 
+	txRecord, err := deployer.waitMined(ctx, "deploy", tx)
+	if err != nil {
+		return "", err
+	}
+
+	gravityRecord := deployer.recordContract("Gravity", gravityAddress, map[string]interface{}{
+		"consuls":  consulsAddress,
+		"bftValue": bftCoefficient,
+	})
+	gravityRecord.ConstructorData = constructorHex(packConstructor(ethereum.GravityABI, consulsAddress, big.NewInt(int64(bftCoefficient))))
+	gravityRecord.Transactions = append(gravityRecord.Transactions, txRecord)
+
 	return gravityAddress.Hex(), nil
 }
@@ -0,0 +1,112 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// GasPolicy controls how transaction fees and gas limits are chosen.
+// Zero values leave the choice to the node suggestions.
+type GasPolicy struct {
+	// Legacy sends pre EIP-1559 transactions with a gas price.
+	Legacy bool
+	// MaxFeePerGas caps the fee cap, or the gas price of legacy transactions.
+	MaxFeePerGas *big.Int
+	// MaxPriorityFeePerGas caps the priority fee of EIP-1559 transactions.
+	MaxPriorityFeePerGas *big.Int
+	// Multiplier is applied to the suggested gas price, priority fee and base fee.
+	Multiplier float64
+	// GasLimits overrides the estimated gas limit per step, keyed by contract name or method.
+	GasLimits map[string]uint64
+	// FeeCeiling is the highest fee in wei a single transaction may cost.
+	FeeCeiling *big.Int
+}
+
+func (deployer *EthDeployer) SetGasPolicy(policy *GasPolicy) {
+	deployer.gasPolicy = policy
+}
+
+func (policy *GasPolicy) multiply(value *big.Int) *big.Int {
+	if policy.Multiplier <= 0 || policy.Multiplier == 1 {
+		return value
+	}
+
+	result, _ := new(big.Float).Mul(new(big.Float).SetInt(value), big.NewFloat(policy.Multiplier)).Int(nil)
+	return result
+}
+
+func capValue(value *big.Int, max *big.Int) *big.Int {
+	if max != nil && value.Cmp(max) > 0 {
+		return new(big.Int).Set(max)
+	}
+
+	return value
+}
+
+// opts returns the transact options for a step with the fees and gas limit of the gas policy.
+func (deployer *EthDeployer) opts(ctx context.Context, step string) (*bind.TransactOpts, error) {
+	opts := *deployer.transactor
+	opts.Context = ctx
+
+	policy := deployer.gasPolicy
+	if policy == nil {
+		return &opts, nil
+	}
+
+	if limit, ok := policy.GasLimits[step]; ok {
+		opts.GasLimit = limit
+	}
+
+	if policy.Legacy {
+		gasPrice, err := deployer.ethClient.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts.GasPrice = capValue(policy.multiply(gasPrice), policy.MaxFeePerGas)
+	} else {
+		head, err := deployer.ethClient.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if head.BaseFee == nil {
+			return nil, fmt.Errorf("node does not support EIP-1559 transactions, use the legacy transaction type")
+		}
+
+		gasTipCap, err := deployer.ethClient.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, err
+		}
+		gasTipCap = capValue(policy.multiply(gasTipCap), policy.MaxPriorityFeePerGas)
+
+		baseFee := policy.multiply(head.BaseFee)
+		gasFeeCap := new(big.Int).Add(gasTipCap, new(big.Int).Mul(baseFee, big.NewInt(2)))
+		gasFeeCap = capValue(gasFeeCap, policy.MaxFeePerGas)
+		gasTipCap = capValue(gasTipCap, gasFeeCap)
+
+		opts.GasFeeCap = gasFeeCap
+		opts.GasTipCap = gasTipCap
+	}
+
+	if policy.FeeCeiling != nil {
+		opts.Signer = feeCeilingSigner(opts.Signer, policy.FeeCeiling, step)
+	}
+
+	return &opts, nil
+}
+
+// feeCeilingSigner refuses to sign, and so to broadcast, transactions whose highest possible fee exceeds the ceiling.
+func feeCeilingSigner(signer bind.SignerFn, ceiling *big.Int, step string) bind.SignerFn {
+	return func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		fee := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+		if fee.Cmp(ceiling) > 0 {
+			return nil, fmt.Errorf("%s fee %s exceeds the fee ceiling %s", step, FormatAmount(fee, 18), FormatAmount(ceiling, 18))
+		}
+
+		return signer(address, tx)
+	}
+}
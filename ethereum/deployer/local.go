@@ -0,0 +1,99 @@
+package deployer
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+)
+
+// localGasLimit is the gas available to a transaction run in the local chain.
+const localGasLimit = 30000000
+
+// localChain runs contracts that are not deployed yet in memory, so they can be read and
+// called before their creation is mined. Calls from these contracts to contracts that only
+// exist on-chain see empty accounts.
+type localChain struct {
+	state *state.StateDB
+	// copied holds the on-chain contracts copied into the local chain, such as the CREATE2 factory
+	copied map[common.Address]bool
+}
+
+func newLocalChain() (*localChain, error) {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localChain{
+		state:  statedb,
+		copied: make(map[common.Address]bool),
+	}, nil
+}
+
+// copyCode makes an on-chain contract available to the local chain, its storage is not copied.
+func (chain *localChain) copyCode(address common.Address, code []byte) {
+	chain.state.SetCode(address, code)
+	chain.copied[address] = true
+}
+
+// has checks whether the contract exists in the local chain.
+func (chain *localChain) has(address common.Address) bool {
+	return chain.state.GetCodeSize(address) > 0
+}
+
+// created checks whether the contract was created in the local chain.
+func (chain *localChain) created(address common.Address) bool {
+	return chain.has(address) && !chain.copied[address]
+}
+
+func (chain *localChain) code(address common.Address) []byte {
+	return chain.state.GetCode(address)
+}
+
+// execute runs a call, or a creation with the nonce when to is nil, and returns the output
+// and the gas used including the intrinsic gas. The state changes are kept only with commit.
+func (chain *localChain) execute(from common.Address, nonce uint64, to *common.Address, value *big.Int,
+	data []byte, commit bool) ([]byte, uint64, error) {
+
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	snapshot := chain.state.Snapshot()
+	// The local chain only checks the contract logic, the sender pays nothing
+	chain.state.AddBalance(from, value)
+
+	cfg := &runtime.Config{
+		Origin:   from,
+		GasLimit: localGasLimit,
+		Value:    value,
+		State:    chain.state,
+	}
+
+	var out []byte
+	var leftOverGas uint64
+	var err error
+	if to == nil {
+		chain.state.SetNonce(from, nonce)
+		out, _, leftOverGas, err = runtime.Create(data, cfg)
+	} else {
+		out, leftOverGas, err = runtime.Call(*to, data, cfg)
+	}
+	if err != nil || !commit {
+		chain.state.RevertToSnapshot(snapshot)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	intrinsicGas, err := core.IntrinsicGas(data, nil, to == nil, true, true)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return out, intrinsicGas + localGasLimit - leftOverGas, nil
+}
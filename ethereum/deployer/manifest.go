@@ -0,0 +1,60 @@
+package deployer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+)
+
+// Manifest is the machine-readable record of a deployment run.
+// The waves deployer writes the same format.
+type Manifest struct {
+	ChainID   string
+	Deployer  string
+	CreatedAt time.Time
+	Contracts []*ContractRecord
+}
+
+type ContractRecord struct {
+	Name string
+	// Port is the name of the port the contract was deployed for
+	Port            string `json:",omitempty"`
+	PortType        string `json:",omitempty"`
+	Address         string
+	ConstructorArgs map[string]interface{} `json:",omitempty"`
+	// ConstructorData holds the encoded constructor arguments, ABI encoded on ethereum
+	// and the binary data entries set on the contract account on waves
+	ConstructorData string `json:",omitempty"`
+	Transactions    []*TxRecord
+}
+
+type TxRecord struct {
+	Step        string
+	TxHash      string
+	BlockNumber uint64 `json:",omitempty"`
+	GasUsed     uint64 `json:",omitempty"`
+	Fee         string `json:",omitempty"`
+}
+
+func (manifest *Manifest) Save(filename string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func LoadManifest(filename string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := new(Manifest)
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
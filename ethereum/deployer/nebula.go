@@ -0,0 +1,144 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Subscription is a subscriber contract of a nebula.
+type Subscription struct {
+	ID               common.Hash
+	Owner            common.Address
+	ContractAddress  common.Address
+	MinConfirmations uint8
+	Reward           *big.Int
+}
+
+// DefaultSubscription is used for ports without configured subscription parameters.
+func DefaultSubscription() *Subscription {
+	return &Subscription{
+		MinConfirmations: 1,
+		Reward:           big.NewInt(0),
+	}
+}
+
+func (s *Subscription) matches(contractAddress common.Address, minConfirmations uint8, reward *big.Int) bool {
+	return s.ContractAddress == contractAddress && s.MinConfirmations == minConfirmations && s.Reward.Cmp(reward) == 0
+}
+
+// subscriptionID computes the id the nebula gives a subscription, the hash of the subscribe
+// selector, the owner, the contract and the minimum confirmations. The reward is not part of it.
+func subscriptionID(owner common.Address, contractAddress common.Address, minConfirmations uint8) common.Hash {
+	selector := crypto.Keccak256([]byte("subscribe(address,uint8,uint256)"))[:4]
+
+	return crypto.Keccak256Hash(selector, owner.Bytes(), contractAddress.Bytes(), []byte{minConfirmations})
+}
+
+// NebulaSubscriptions reads every subscription of the nebula.
+func NebulaSubscriptions(backend Backend, nebulaAddress common.Address) ([]*Subscription, error) {
+	nebula, err := ethereum.NewNebula(nebulaAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return nebulaSubscriptions(nebula)
+}
+
+func nebulaSubscriptions(nebula *ethereum.Nebula) ([]*Subscription, error) {
+	ids, err := nebula.GetSubscribersIds(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscriptions []*Subscription
+	for _, id := range ids {
+		subscription, err := nebula.Subscriptions(nil, id)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, &Subscription{
+			ID:               id,
+			Owner:            subscription.Owner,
+			ContractAddress:  subscription.ContractAddress,
+			MinConfirmations: subscription.MinConfirmations,
+			Reward:           subscription.Reward,
+		})
+	}
+
+	return subscriptions, nil
+}
+
+// Subscribe subscribes the contract to the nebula and returns the subscription read on-chain.
+// An existing subscription with the same parameters is returned without a transaction, while
+// a subscription of the contract with other parameters is an error, the nebula keeps both.
+func (deployer *EthDeployer) Subscribe(nebulaAddress common.Address, contractAddress common.Address,
+	minConfirmations uint8, reward *big.Int, ctx context.Context) (*Subscription, error) {
+
+	nebula, err := ethereum.NewNebula(nebulaAddress, deployer.ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := nebulaSubscriptions(nebula)
+	if err != nil {
+		return nil, err
+	}
+	for _, subscription := range subscriptions {
+		if subscription.matches(contractAddress, minConfirmations, reward) {
+			fmt.Printf("Already subscribed: %s \n", contractAddress.Hex())
+			return subscription, nil
+		}
+	}
+	for _, subscription := range subscriptions {
+		if subscription.ContractAddress == contractAddress {
+			return nil, fmt.Errorf("contract %s is already subscribed to nebula %s as %s with %d confirmations and reward %s",
+				contractAddress.Hex(), nebulaAddress.Hex(), subscription.ID.Hex(), subscription.MinConfirmations, subscription.Reward)
+		}
+	}
+
+	opts, err := deployer.opts(ctx, "subscribe")
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := nebula.Subscribe(opts, contractAddress, minConfirmations, reward)
+	if err != nil {
+		return nil, err
+	}
+
+	txRecord, err := deployer.waitMined(ctx, "subscribe", tx)
+	if err != nil {
+		return nil, err
+	}
+	deployer.recordTransaction("Nebula", nebulaAddress, txRecord)
+
+	if deployer.planning() {
+		return &Subscription{
+			ID:               subscriptionID(deployer.transactor.From, contractAddress, minConfirmations),
+			Owner:            deployer.transactor.From,
+			ContractAddress:  contractAddress,
+			MinConfirmations: minConfirmations,
+			Reward:           reward,
+		}, nil
+	}
+
+	subscriptions, err = nebulaSubscriptions(nebula)
+	if err != nil {
+		return nil, err
+	}
+	for _, subscription := range subscriptions {
+		if subscription.matches(contractAddress, minConfirmations, reward) {
+			fmt.Printf("Subscription id: %s \n", subscription.ID.Hex())
+			return subscription, nil
+		}
+	}
+
+	return nil, fmt.Errorf("subscription of %s is missing in nebula %s after transaction %s", contractAddress.Hex(), nebulaAddress.Hex(), txRecord.TxHash)
+}
@@ -0,0 +1,125 @@
+package deployer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSubscribeOtherParameters(t *testing.T) {
+	chain := newSafeChain(t)
+	ctx := context.Background()
+	ethDeployer := NewEthDeployer(chain.backend, chain.transactor(t))
+
+	nebula, err := ethDeployer.DeployNebula(chain.gravity, 2, []common.Address{chain.executor}, 1, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nebulaAddress := common.HexToAddress(nebula)
+	subscriber := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	subscription, err := ethDeployer.Subscribe(nebulaAddress, subscriber, 1, big.NewInt(0), ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	same, err := ethDeployer.Subscribe(nebulaAddress, subscriber, 1, big.NewInt(0), ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same.ID != subscription.ID {
+		t.Errorf("expected the existing subscription %s, got %s", subscription.ID.Hex(), same.ID.Hex())
+	}
+
+	_, err = ethDeployer.Subscribe(nebulaAddress, subscriber, 2, big.NewInt(0), ctx)
+	if err == nil {
+		t.Error("expected an error for a subscription with other parameters")
+	}
+
+	subscriptions, err := NebulaSubscriptions(chain.backend, nebulaAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subscriptions) != 1 {
+		t.Errorf("expected 1 subscription, got %d", len(subscriptions))
+	}
+}
+
+func TestUpdateOracles(t *testing.T) {
+	chain := newSafeChain(t)
+	ctx := context.Background()
+	ethDeployer := NewEthDeployer(chain.backend, chain.transactor(t))
+
+	nebula, err := ethDeployer.DeployNebula(chain.gravity, 2, []common.Address{chain.executor}, 1, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nebulaAddress := common.HexToAddress(nebula)
+
+	oracles := []common.Address{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+	}
+	signature, err := crypto.Sign(OraclesMessageHash(oracles).Bytes(), chain.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update, err := NewOraclesUpdate(chain.backend, nebulaAddress, oracles, big.NewInt(1), [][]byte{signature})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Quorum != 1 || update.Signatures.Count() != 1 {
+		t.Fatalf("expected 1 of 1 signatures, got %d of %d", update.Signatures.Count(), update.Quorum)
+	}
+
+	_, err = ethDeployer.UpdateOracles(update, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewOraclesUpdate(chain.backend, nebulaAddress, oracles, big.NewInt(1), [][]byte{signature})
+	if err == nil {
+		t.Error("expected an error for a used round")
+	}
+}
+
+func TestResumeNebula(t *testing.T) {
+	chain := newSafeChain(t)
+	ctx := context.Background()
+	ethDeployer := NewEthDeployer(chain.backend, chain.transactor(t))
+
+	state := new(PortState)
+	oracles := []common.Address{chain.executor}
+	nebula, err := ethDeployer.DeployNebula(chain.gravity, 2, oracles, 1, state, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the library the nebula is linked to is paid for as well
+	var recordedGas uint64
+	for _, record := range ethDeployer.Manifest(big.NewInt(SimulatedChainID)).Contracts {
+		for _, txRecord := range record.Transactions {
+			recordedGas += txRecord.GasUsed
+		}
+	}
+	if state.contract("QueueLib") == nil || recordedGas == 0 || recordedGas != ethDeployer.GasUsed() {
+		t.Errorf("expected the QueueLib and nebula gas %d in the manifest, got %d", ethDeployer.GasUsed(), recordedGas)
+	}
+
+	resumed, err := ethDeployer.DeployNebula(chain.gravity, 2, oracles, 1, state, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed != nebula {
+		t.Errorf("expected the nebula %s from the state, got %s", nebula, resumed)
+	}
+
+	_, err = ethDeployer.DeployNebula(chain.gravity, 2, oracles, 2, state, ctx)
+	if err == nil {
+		t.Error("expected an error for a nebula with another bft value")
+	}
+}
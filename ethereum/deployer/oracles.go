@@ -0,0 +1,147 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OraclesUpdate is a new oracle set of a nebula with the consul signatures collected for it.
+type OraclesUpdate struct {
+	NebulaAddress common.Address
+	Oracles       []common.Address
+	Round         *big.Int
+	Signatures    *ConsulSignatures
+	// Quorum is the bft coefficient of gravity
+	Quorum int
+	// NebulaBft is the number of signatures the nebula checks for
+	NebulaBft int
+}
+
+// NewOraclesUpdate checks the round is not used by the nebula yet and assigns the signatures to the consuls
+// of the gravity contract of the nebula. The message hash is checked against the one computed by the nebula.
+func NewOraclesUpdate(backend Backend, nebulaAddress common.Address, oracles []common.Address, round *big.Int, signatures [][]byte) (*OraclesUpdate, error) {
+	nebula, err := ethereum.NewNebula(nebulaAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	gravityAddress, err := nebula.GravityContract(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := ReadGravity(backend, gravityAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := nebula.Rounds(nil, round)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, fmt.Errorf("round %s is already used by nebula %s", round, nebulaAddress.Hex())
+	}
+
+	nebulaBft, err := nebula.BftValue(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := OraclesMessageHash(oracles)
+	contractHash, err := nebula.HashNewOracles(nil, oracles)
+	if err != nil {
+		return nil, err
+	}
+	if common.Hash(contractHash) != hash {
+		return nil, fmt.Errorf("message hash %s differs from the nebula hash %s", hash.Hex(), common.Hash(contractHash).Hex())
+	}
+
+	assigned, err := AssignSignatures(hash, info.Consuls, signatures)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OraclesUpdate{
+		NebulaAddress: nebulaAddress,
+		Oracles:       oracles,
+		Round:         round,
+		Signatures:    assigned,
+		Quorum:        int(info.BftCoefficient.Int64()),
+		NebulaBft:     int(nebulaBft.Int64()),
+	}, nil
+}
+
+func (update *OraclesUpdate) Approvals() (*ConsulSignatures, int) {
+	return update.Signatures, update.Quorum
+}
+
+// CheckQuorum fails when fewer consuls signed the update than the quorum or than the nebula checks for.
+func (update *OraclesUpdate) CheckQuorum() error {
+	err := update.Signatures.checkQuorum(update.Quorum)
+	if err != nil {
+		return err
+	}
+	if update.Signatures.Count() < update.NebulaBft {
+		return fmt.Errorf("%d consul signatures, nebula %s requires %d",
+			update.Signatures.Count(), update.NebulaAddress.Hex(), update.NebulaBft)
+	}
+
+	return nil
+}
+
+// UpdateOracles submits the oracle update once the quorum is reached and confirms the new oracles and round on-chain.
+func (deployer *EthDeployer) UpdateOracles(update *OraclesUpdate, ctx context.Context) (string, error) {
+	err := update.CheckQuorum()
+	if err != nil {
+		return "", err
+	}
+
+	nebula, err := ethereum.NewNebula(update.NebulaAddress, deployer.ethClient)
+	if err != nil {
+		return "", err
+	}
+
+	opts, err := deployer.opts(ctx, "updateOracles")
+	if err != nil {
+		return "", err
+	}
+
+	signatures := update.Signatures
+	tx, err := nebula.UpdateOracles(opts, update.Oracles, signatures.V, signatures.R, signatures.S, update.Round)
+	if err != nil {
+		return "", err
+	}
+
+	txRecord, err := deployer.waitMined(ctx, "updateOracles", tx)
+	if err != nil {
+		return "", err
+	}
+	deployer.recordTransaction("Nebula", update.NebulaAddress, txRecord)
+	if deployer.planning() {
+		return tx.Hash().Hex(), nil
+	}
+
+	used, err := nebula.Rounds(nil, update.Round)
+	if err != nil {
+		return "", err
+	}
+	if !used {
+		return "", fmt.Errorf("nebula %s has no round %s after the update", update.NebulaAddress.Hex(), update.Round)
+	}
+	oracles, err := nebula.GetOracles(nil)
+	if err != nil {
+		return "", err
+	}
+	if formatAddresses(oracles) != formatAddresses(update.Oracles) {
+		return "", fmt.Errorf("nebula %s has oracles %s after the update, expected %s",
+			update.NebulaAddress.Hex(), formatAddresses(oracles), formatAddresses(update.Oracles))
+	}
+
+	return tx.Hash().Hex(), nil
+}
@@ -0,0 +1,151 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ownableABI is the part of OpenZeppelin Ownable used to hand over deployed contracts.
+const ownableABI = `[
+	{"inputs":[],"name":"owner","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"internalType":"address","name":"newOwner","type":"address"}],"name":"transferOwnership","outputs":[],"stateMutability":"nonpayable","type":"function"}
+]`
+
+// hasMethod calls a view method of the contract to check it is implemented. Only a revert means
+// the contract does not implement the method, any other failure of the call is an error.
+func hasMethod(contract *bind.BoundContract, address common.Address, method string, ctx context.Context, args ...interface{}) (bool, error) {
+	var out []interface{}
+	err := contract.Call(&bind.CallOpts{Context: ctx}, &out, method, args...)
+	if err == nil {
+		return true, nil
+	}
+	if isRevert(err) {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("call %s of contract %s: %w", method, address.Hex(), err)
+}
+
+// isRevert checks whether the call failed because the contract reverted. Nodes only return
+// the revert as an error message.
+func isRevert(err error) bool {
+	return errors.Is(err, vm.ErrExecutionReverted) || strings.Contains(err.Error(), vm.ErrExecutionReverted.Error())
+}
+
+func (deployer *EthDeployer) boundContract(address common.Address, contractAbi string) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractAbi))
+	if err != nil {
+		return nil, err
+	}
+
+	return bind.NewBoundContract(address, parsed, deployer.ethClient, deployer.ethClient, deployer.ethClient), nil
+}
+
+func contractOwner(contract *bind.BoundContract, ctx context.Context) (common.Address, error) {
+	var out []interface{}
+	err := contract.Call(&bind.CallOpts{Context: ctx}, &out, "owner")
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+// contractRecord returns the manifest record of a contract deployed or resumed in this run.
+func (deployer *EthDeployer) contractRecord(address common.Address) *ContractRecord {
+	for _, record := range deployer.contracts {
+		if common.HexToAddress(record.Address) == address {
+			return record
+		}
+	}
+
+	return nil
+}
+
+// TransferOwnership transfers every ownable contract to the new owner and confirms the owner on-chain.
+// Contracts without an owner and contracts already owned by the new owner are skipped.
+func (deployer *EthDeployer) TransferOwnership(contracts []common.Address, newOwner common.Address, ctx context.Context) error {
+	for _, address := range contracts {
+		contract, err := deployer.boundContract(address, ownableABI)
+		if err != nil {
+			return err
+		}
+
+		ownable, err := hasMethod(contract, address, "owner", ctx)
+		if err != nil {
+			return err
+		}
+		if !ownable {
+			fmt.Printf("Not ownable, skip: %s \n", address.Hex())
+			continue
+		}
+
+		txRecord, err := deployer.transferOwnership(address, newOwner, ctx)
+		if err != nil {
+			return err
+		}
+		if txRecord != nil {
+			deployer.recordTransaction("Contract", address, txRecord)
+		}
+	}
+
+	return nil
+}
+
+// transferOwnership transfers an ownable contract and confirms the new owner.
+// The transaction record is nil when the contract is already owned by the new owner.
+func (deployer *EthDeployer) transferOwnership(address common.Address, newOwner common.Address, ctx context.Context) (*TxRecord, error) {
+	contract, err := deployer.boundContract(address, ownableABI)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := contractOwner(contract, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if owner == newOwner {
+		fmt.Printf("Already owned by %s: %s \n", newOwner.Hex(), address.Hex())
+		return nil, nil
+	}
+	if owner != deployer.transactor.From {
+		return nil, fmt.Errorf("contract %s is owned by %s, not by the deployer", address.Hex(), owner.Hex())
+	}
+
+	opts, err := deployer.opts(ctx, "transferOwnership")
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.Transact(opts, "transferOwnership", newOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	txRecord, err := deployer.waitMined(ctx, "transferOwnership", tx)
+	if err != nil {
+		return nil, err
+	}
+	if deployer.planning() {
+		return txRecord, nil
+	}
+
+	owner, err = contractOwner(contract, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if owner != newOwner {
+		return nil, fmt.Errorf("contract %s is owned by %s after the transfer, expected %s", address.Hex(), owner.Hex(), newOwner.Hex())
+	}
+
+	fmt.Printf("Ownership transferred to %s: %s \n", newOwner.Hex(), address.Hex())
+
+	return txRecord, nil
+}
@@ -0,0 +1,202 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultSubscribeGasLimit is used for pipelined subscriptions, which cannot be estimated
+// before the nebula is mined.
+const DefaultSubscribeGasLimit = 300000
+
+type pipelinedTx struct {
+	record *ContractRecord
+	step   string
+	tx     *types.Transaction
+}
+
+// DeployPortPipelined sends the QueueLib, nebula, port and subscribe transactions back to back
+// with locally managed nonces, using the addresses predicted from the deployer nonce.
+// It falls back to DeployPort when the state holds steps of a previous run or CREATE2 is used.
+func (deployer *EthDeployer) DeployPortPipelined(gravityAddress string, dataType int, existingToken string,
+	oracles []common.Address, bftCoefficient int, portType PortType, subscription *Subscription, state *PortState, ctx context.Context) (*GatewayPort, error) {
+
+	if state == nil {
+		state = new(PortState)
+	}
+	if subscription == nil {
+		subscription = DefaultSubscription()
+	}
+	if len(state.Contracts) > 0 {
+		fmt.Println("Resuming a previous deployment step by step")
+		return deployer.DeployPort(gravityAddress, dataType, existingToken, oracles, bftCoefficient, portType, subscription, state, ctx)
+	}
+	if deployer.create2 != nil {
+		fmt.Println("CREATE2 addresses do not depend on nonces, deploying step by step")
+		return deployer.DeployPort(gravityAddress, dataType, existingToken, oracles, bftCoefficient, portType, subscription, state, ctx)
+	}
+
+	erc20Address := common.HexToAddress(existingToken)
+
+	fmt.Printf("ERC20: %v \n", erc20Address.String())
+
+	nonce, err := deployer.ethClient.PendingNonceAt(ctx, deployer.transactor.From)
+	if err != nil {
+		return nil, err
+	}
+
+	queueLibAddress := crypto.CreateAddress(deployer.transactor.From, nonce)
+	nebulaAddress := crypto.CreateAddress(deployer.transactor.From, nonce+1)
+	portAddress := crypto.CreateAddress(deployer.transactor.From, nonce+2)
+
+	fmt.Printf("Predicted QueueLib address: %s \n", queueLibAddress.Hex())
+	fmt.Printf("Predicted nebula address: %s \n", nebulaAddress.Hex())
+	fmt.Printf("Predicted port address: %s \n", portAddress.Hex())
+
+	var sent []*pipelinedTx
+
+	opts, err := deployer.opts(ctx, "QueueLib")
+	if err != nil {
+		return nil, err
+	}
+	opts.Nonce = new(big.Int).SetUint64(nonce)
+
+	deployedQueueLib, tx, err := deployer.deployQueueLib(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if deployedQueueLib != queueLibAddress {
+		return nil, fmt.Errorf("QueueLib deployed to %s, predicted %s", deployedQueueLib.Hex(), queueLibAddress.Hex())
+	}
+
+	queueLibRecord := &ContractRecord{
+		Name:    "QueueLib",
+		Address: queueLibAddress.Hex(),
+	}
+	sent = append(sent, &pipelinedTx{record: queueLibRecord, step: "deploy", tx: tx})
+
+	opts, err = deployer.opts(ctx, "Nebula")
+	if err != nil {
+		return nil, err
+	}
+	opts.Nonce = new(big.Int).SetUint64(nonce + 1)
+
+	deployedNebula, tx, err := deployer.deployNebula(ctx, opts, queueLibAddress, gravityAddress, dataType, oracles, bftCoefficient)
+	if err != nil {
+		return nil, err
+	}
+	if deployedNebula != nebulaAddress {
+		return nil, fmt.Errorf("nebula deployed to %s, predicted %s", deployedNebula.Hex(), nebulaAddress.Hex())
+	}
+
+	nebulaRecord := &ContractRecord{
+		Name:            "Nebula",
+		PortType:        portType.Format(),
+		Address:         nebulaAddress.Hex(),
+		ConstructorArgs: nebulaArgs(common.HexToAddress(gravityAddress), dataType, oracles, bftCoefficient),
+		ConstructorData: constructorHex(nebulaConstructor(gravityAddress, dataType, oracles, bftCoefficient)),
+	}
+	sent = append(sent, &pipelinedTx{record: nebulaRecord, step: "deploy", tx: tx})
+
+	opts, err = deployer.opts(ctx, portType.ContractName())
+	if err != nil {
+		return nil, err
+	}
+	opts.Nonce = new(big.Int).SetUint64(nonce + 2)
+
+	deployedPort, tx, err := deployer.deployPortContract(ctx, opts, portType, nebulaAddress, erc20Address)
+	if err != nil {
+		return nil, err
+	}
+	if deployedPort != portAddress {
+		return nil, fmt.Errorf("port deployed to %s, predicted %s", deployedPort.Hex(), portAddress.Hex())
+	}
+
+	portRecord := &ContractRecord{
+		Name:            portType.ContractName(),
+		PortType:        portType.Format(),
+		Address:         portAddress.Hex(),
+		ConstructorArgs: portArgs(nebulaAddress, erc20Address),
+		ConstructorData: constructorHex(portConstructor(portType, nebulaAddress, erc20Address)),
+	}
+	sent = append(sent, &pipelinedTx{record: portRecord, step: "deploy", tx: tx})
+
+	nebula, err := ethereum.NewNebula(nebulaAddress, deployer.ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err = deployer.opts(ctx, "subscribe")
+	if err != nil {
+		return nil, err
+	}
+	opts.Nonce = new(big.Int).SetUint64(nonce + 3)
+	if opts.GasLimit == 0 {
+		opts.GasLimit = DefaultSubscribeGasLimit
+	}
+
+	tx, err = nebula.Subscribe(opts, portAddress, subscription.MinConfirmations, subscription.Reward)
+	if err != nil {
+		return nil, err
+	}
+	sent = append(sent, &pipelinedTx{record: nebulaRecord, step: "subscribe", tx: tx})
+
+	// every mined deployment is saved to the state at once, so a rerun after a failed
+	// transaction resumes the contracts deployed before it
+	for _, pipelined := range sent {
+		txRecord, err := deployer.waitMined(ctx, pipelined.step, pipelined.tx)
+		if err != nil {
+			return nil, err
+		}
+		record := pipelined.record
+		record.Transactions = append(record.Transactions, txRecord)
+		if pipelined.step != "deploy" {
+			continue
+		}
+
+		deployed, err := deployer.hasCode(common.HexToAddress(record.Address), ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !deployed {
+			return nil, fmt.Errorf("%s is not deployed at the predicted address %s", record.Name, record.Address)
+		}
+
+		deployer.contracts = append(deployer.contracts, record)
+		err = state.complete(record)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	subscribed, err := contractSubscription(nebula, portAddress)
+	if err != nil {
+		return nil, err
+	}
+	if subscribed == nil {
+		return nil, fmt.Errorf("port %s is not subscribed to nebula %s", portAddress.Hex(), nebulaAddress.Hex())
+	}
+	if !subscribed.matches(portAddress, subscription.MinConfirmations, subscription.Reward) {
+		return nil, fmt.Errorf("port %s is subscribed to nebula %s with %d confirmations and reward %s", portAddress.Hex(),
+			nebulaAddress.Hex(), subscribed.MinConfirmations, subscribed.Reward)
+	}
+
+	state.Subscribed = true
+	err = state.Save()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GatewayPort{
+		PortAddress:   portAddress.Hex(),
+		NebulaAddress: nebulaAddress.Hex(),
+		ERC20Address:  erc20Address.Hex(),
+	}, nil
+}
@@ -0,0 +1,58 @@
+package deployer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDeployPortPipelined(t *testing.T) {
+	chain := newSafeChain(t)
+	ctx := context.Background()
+	ethDeployer := NewEthDeployer(chain.backend, chain.transactor(t))
+
+	state := new(PortState)
+	oracles := []common.Address{chain.executor}
+	token := "0x1111111111111111111111111111111111111111"
+	port, err := ethDeployer.DeployPortPipelined(chain.gravity, 2, token, oracles, 1, IBPort, nil, state, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{"QueueLib", "Nebula", IBPort.ContractName()}
+	if len(state.Contracts) != len(names) {
+		t.Fatalf("expected %d contracts in the state, got %d", len(names), len(state.Contracts))
+	}
+	for i, record := range state.Contracts {
+		if record.Name != names[i] {
+			t.Errorf("expected %s, got %s", names[i], record.Name)
+		}
+		deployed, err := ethDeployer.hasCode(common.HexToAddress(record.Address), ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !deployed {
+			t.Errorf("%s is not deployed at %s", record.Name, record.Address)
+		}
+	}
+	if !state.Subscribed {
+		t.Error("expected the port to be subscribed")
+	}
+
+	portNebula, _, err := ethDeployer.portContracts(IBPort, common.HexToAddress(port.PortAddress), ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if portNebula != common.HexToAddress(port.NebulaAddress) {
+		t.Errorf("expected port nebula %s, got %s", port.NebulaAddress, portNebula.Hex())
+	}
+
+	resumed, err := ethDeployer.DeployPortPipelined(chain.gravity, 2, token, oracles, 1, IBPort, nil, state, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *resumed != *port {
+		t.Errorf("expected the port %+v from the state, got %+v", port, resumed)
+	}
+}
@@ -0,0 +1,250 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// localGasMargin is added to the gas used by calls to planned contracts, as a share of it,
+// for the gas the EVM withholds from nested calls.
+const localGasMargin = 4
+
+// planBackend records the transactions of a plan instead of sending them. The contracts
+// the plan creates run in a local chain, every other read goes to the node.
+type planBackend struct {
+	Backend
+	local *localChain
+	from  common.Address
+	nonce uint64
+	// safe plans the transactions of a Safe, see NewSafePlanner
+	safe bool
+	sent []*types.Transaction
+}
+
+func (backend *planBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	if account == backend.from {
+		return backend.nonce, nil
+	}
+
+	return backend.Backend.PendingNonceAt(ctx, account)
+}
+
+func (backend *planBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if backend.local.has(contract) {
+		return backend.local.code(contract), nil
+	}
+
+	return backend.Backend.CodeAt(ctx, contract, blockNumber)
+}
+
+func (backend *planBackend) PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
+	if backend.local.has(contract) {
+		return backend.local.code(contract), nil
+	}
+
+	return backend.Backend.PendingCodeAt(ctx, contract)
+}
+
+func (backend *planBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.To != nil && backend.local.has(*call.To) {
+		out, _, err := backend.local.execute(call.From, 0, call.To, call.Value, call.Data, false)
+		return out, err
+	}
+
+	return backend.Backend.CallContract(ctx, call, blockNumber)
+}
+
+func (backend *planBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	if call.To != nil && backend.local.created(*call.To) {
+		_, gas, err := backend.local.execute(call.From, 0, call.To, call.Value, call.Data, false)
+		if err != nil {
+			return 0, err
+		}
+
+		return gas + gas/localGasMargin, nil
+	}
+
+	return backend.Backend.EstimateGas(ctx, call)
+}
+
+// HeaderByNumber hides the base fee from a Safe plan, so the transactions get a zero gas price.
+func (backend *planBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := backend.Backend.HeaderByNumber(ctx, number)
+	if err != nil || !backend.safe {
+		return header, err
+	}
+
+	header = types.CopyHeader(header)
+	header.BaseFee = nil
+
+	return header, nil
+}
+
+// SuggestGasPrice is zero for a Safe plan, the Safe transactions are paid by whoever executes them.
+func (backend *planBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	if backend.safe {
+		return new(big.Int), nil
+	}
+
+	return backend.Backend.SuggestGasPrice(ctx)
+}
+
+// SendTransaction records the transaction. Creations and calls to contracts of the local
+// chain run there, so a transaction that would revert fails the plan.
+func (backend *planBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if tx.To() == nil || backend.local.has(*tx.To()) {
+		_, _, err := backend.local.execute(backend.from, tx.Nonce(), tx.To(), tx.Value(), tx.Data(), true)
+		if err != nil {
+			return fmt.Errorf("planned transaction fails: %w", err)
+		}
+	}
+
+	backend.sent = append(backend.sent, tx)
+	// The account nonce of a Safe only grows with the contracts it creates
+	if !backend.safe || tx.To() == nil {
+		backend.nonce++
+	}
+
+	return nil
+}
+
+// TransactionReceipt returns a successful receipt for recorded transactions, so the
+// deployer does not wait for them. Nothing is used of the gas limit before the transaction is mined.
+func (backend *planBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	for _, tx := range backend.sent {
+		if tx.Hash() != txHash {
+			continue
+		}
+
+		head, err := backend.Backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		receipt := &types.Receipt{
+			Type:        tx.Type(),
+			Status:      types.ReceiptStatusSuccessful,
+			TxHash:      txHash,
+			BlockNumber: head.Number,
+		}
+		if tx.To() == nil {
+			receipt.ContractAddress = crypto.CreateAddress(backend.from, tx.Nonce())
+		}
+
+		return receipt, nil
+	}
+
+	return backend.Backend.TransactionReceipt(ctx, txHash)
+}
+
+// Planner builds a bundle of unsigned transactions with consecutive nonces of the sender by running
+// the deployer against a backend that records the transactions instead of sending them.
+// Contract addresses are predicted from the nonces or CREATE2.
+type Planner struct {
+	deployer  *EthDeployer
+	backend   *planBackend
+	chainID   *big.Int
+	createdAt time.Time
+}
+
+// NewPlanner starts a plan for the sender at its pending nonce.
+func NewPlanner(backend Backend, from common.Address, chainID *big.Int, ctx context.Context) (*Planner, error) {
+	nonce, err := backend.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := newLocalChain()
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := &planBackend{
+		Backend: backend,
+		local:   local,
+		from:    from,
+		nonce:   nonce,
+	}
+	transactor := &bind.TransactOpts{
+		From: from,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}
+
+	return &Planner{
+		deployer:  NewEthDeployer(recorder, transactor),
+		backend:   recorder,
+		chainID:   chainID,
+		createdAt: time.Now().UTC(),
+	}, nil
+}
+
+// Deployer returns the deployer whose transactions are added to the plan.
+func (planner *Planner) Deployer() *EthDeployer {
+	return planner.deployer
+}
+
+// SetCreate2 deploys the planned contracts through the CREATE2 factory,
+// which is copied into the local chain to create them there as well.
+// A Safe plan keeps the default factory without one.
+func (planner *Planner) SetCreate2(create2 *Create2, ctx context.Context) error {
+	if create2 == nil && planner.backend.safe {
+		create2 = &Create2{Factory: common.HexToAddress(DefaultCreate2Factory)}
+	}
+	planner.deployer.SetCreate2(create2)
+	if create2 == nil {
+		return nil
+	}
+
+	code, err := planner.backend.Backend.CodeAt(ctx, create2.Factory, nil)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("CREATE2 factory %s is not deployed", create2.Factory.Hex())
+	}
+	planner.backend.local.copyCode(create2.Factory, code)
+
+	return nil
+}
+
+// Bundle returns the transactions planned so far, named after the manifest records they belong to.
+func (planner *Planner) Bundle() *Bundle {
+	bundle := &Bundle{
+		ChainID:   planner.chainID.String(),
+		From:      planner.backend.from.Hex(),
+		CreatedAt: planner.createdAt,
+	}
+
+	for _, tx := range planner.backend.sent {
+		planned := &BundleTx{Transaction: tx}
+		for _, record := range planner.deployer.contracts {
+			for _, txRecord := range record.Transactions {
+				if txRecord.TxHash != tx.Hash().Hex() {
+					continue
+				}
+
+				planned.Step = txRecord.Step
+				planned.Contract = record.Name
+				planned.Address = record.Address
+				if tx.To() == nil || txRecord.Step == "deploy" {
+					deployment := *record
+					deployment.Transactions = nil
+					planned.Deployment = &deployment
+				}
+			}
+		}
+		bundle.Transactions = append(bundle.Transactions, planned)
+	}
+
+	return bundle
+}
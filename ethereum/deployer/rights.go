@@ -0,0 +1,165 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// accessControlABI is the part of OpenZeppelin AccessControl used to grant token roles.
+const accessControlABI = `[
+	{"inputs":[{"internalType":"bytes32","name":"role","type":"bytes32"},{"internalType":"address","name":"account","type":"address"}],"name":"hasRole","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"internalType":"bytes32","name":"role","type":"bytes32"},{"internalType":"address","name":"account","type":"address"}],"name":"grantRole","outputs":[],"stateMutability":"nonpayable","type":"function"},
+	{"inputs":[],"name":"MINTER_ROLE","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"BURNER_ROLE","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"}
+]`
+
+// DefaultAdminRole administers every other role of an AccessControl contract.
+var DefaultAdminRole = common.Hash{}
+
+// GrantPortRights gives the IB port the right to mint and burn the token and confirms it on-chain.
+// Tokens with roles get the minter and, when defined, burner role granted to the port.
+// Ownable tokens are transferred to the port only with transferOwnership. The transactions are recorded on the port.
+func (deployer *EthDeployer) GrantPortRights(portAddress common.Address, tokenAddress common.Address,
+	transferOwnership bool, ctx context.Context) error {
+
+	var txRecords []*TxRecord
+
+	token, err := deployer.boundContract(tokenAddress, accessControlABI)
+	if err != nil {
+		return err
+	}
+	withRoles, err := hasMethod(token, tokenAddress, "hasRole", ctx, DefaultAdminRole, deployer.transactor.From)
+	if err != nil {
+		return err
+	}
+
+	ownableToken, err := deployer.boundContract(tokenAddress, ownableABI)
+	if err != nil {
+		return err
+	}
+	ownable, err := hasMethod(ownableToken, tokenAddress, "owner", ctx)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case withRoles:
+		txRecords, err = deployer.grantTokenRoles(portAddress, tokenAddress, ctx)
+	case ownable && !transferOwnership:
+		return fmt.Errorf("token %s is ownable, enable the transfer of its ownership to port %s", tokenAddress.Hex(), portAddress.Hex())
+	case ownable:
+		var txRecord *TxRecord
+		txRecord, err = deployer.transferOwnership(tokenAddress, portAddress, ctx)
+		if txRecord != nil {
+			txRecords = append(txRecords, txRecord)
+		}
+	default:
+		return fmt.Errorf("token %s has neither roles nor an owner, grant the port mint and burn rights manually", tokenAddress.Hex())
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, txRecord := range txRecords {
+		deployer.recordTransaction(IBPort.ContractName(), portAddress, txRecord)
+	}
+
+	return nil
+}
+
+func (deployer *EthDeployer) grantTokenRoles(portAddress common.Address, tokenAddress common.Address, ctx context.Context) ([]*TxRecord, error) {
+	token, err := deployer.boundContract(tokenAddress, accessControlABI)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []string{"MINTER_ROLE"}
+	withBurner, err := hasMethod(token, tokenAddress, "BURNER_ROLE", ctx)
+	if err != nil {
+		return nil, err
+	}
+	if withBurner {
+		roles = append(roles, "BURNER_ROLE")
+	}
+
+	isAdmin, err := hasRole(token, DefaultAdminRole, deployer.transactor.From, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var txRecords []*TxRecord
+	for _, name := range roles {
+		role, err := deployer.tokenRole(token, tokenAddress, name, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		granted, err := hasRole(token, role, portAddress, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if granted {
+			fmt.Printf("Port already has %s: %s \n", name, portAddress.Hex())
+			continue
+		}
+		if !isAdmin {
+			return nil, fmt.Errorf("deployer is not an admin of token %s, cannot grant %s", tokenAddress.Hex(), name)
+		}
+
+		opts, err := deployer.opts(ctx, "grantRole")
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err := token.Transact(opts, "grantRole", role, portAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		txRecord, err := deployer.waitMined(ctx, "grantRole", tx)
+		if err != nil {
+			return nil, err
+		}
+		txRecords = append(txRecords, txRecord)
+		if deployer.planning() {
+			continue
+		}
+
+		granted, err = hasRole(token, role, portAddress, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !granted {
+			return nil, fmt.Errorf("port %s does not have %s after the grant", portAddress.Hex(), name)
+		}
+
+		fmt.Printf("Granted %s to port: %s \n", name, portAddress.Hex())
+	}
+
+	return txRecords, nil
+}
+
+// tokenRole reads the role id from the token.
+func (deployer *EthDeployer) tokenRole(token *bind.BoundContract, tokenAddress common.Address, name string, ctx context.Context) (common.Hash, error) {
+	var out []interface{}
+	err := token.Call(&bind.CallOpts{Context: ctx}, &out, name)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("read %s of token %s: %w", name, tokenAddress.Hex(), err)
+	}
+
+	return common.Hash(*abi.ConvertType(out[0], new([32]byte)).(*[32]byte)), nil
+}
+
+func hasRole(token *bind.BoundContract, role common.Hash, account common.Address, ctx context.Context) (bool, error) {
+	var out []interface{}
+	err := token.Call(&bind.CallOpts{Context: ctx}, &out, "hasRole", role, account)
+	if err != nil {
+		return false, err
+	}
+
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
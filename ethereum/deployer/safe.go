@@ -0,0 +1,121 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TxBuilderVersion is the Safe Transaction Builder version whose batch file format proposals use.
+const TxBuilderVersion = "1.16.1"
+
+// SafeProposal is a batch file of the Safe Transaction Builder. The builder executes the
+// transactions of a batch in order, as a single MultiSend transaction when there are several.
+type SafeProposal struct {
+	Version      string           `json:"version"`
+	ChainID      string           `json:"chainId"`
+	CreatedAt    int64            `json:"createdAt"`
+	Meta         SafeProposalMeta `json:"meta"`
+	Transactions []*SafeBuilderTx `json:"transactions"`
+}
+
+type SafeProposalMeta struct {
+	Name                   string `json:"name"`
+	Description            string `json:"description"`
+	TxBuilderVersion       string `json:"txBuilderVersion"`
+	CreatedFromSafeAddress string `json:"createdFromSafeAddress"`
+}
+
+// SafeBuilderTx is a call of the Safe, the builder has no delegate calls.
+type SafeBuilderTx struct {
+	To    string `json:"to"`
+	Value string `json:"value"`
+	Data  string `json:"data"`
+}
+
+// NewSafePlanner starts a plan of transactions executed by the Safe. The plan creates contracts
+// through the default CREATE2 factory unless another one is set, contracts the factory would own are refused.
+func NewSafePlanner(backend Backend, safe common.Address, chainID *big.Int, ctx context.Context) (*Planner, error) {
+	code, err := backend.CodeAt(ctx, safe, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("safe %s is not deployed", safe.Hex())
+	}
+
+	planner, err := NewPlanner(backend, safe, chainID, ctx)
+	if err != nil {
+		return nil, err
+	}
+	planner.backend.safe = true
+
+	err = planner.SetCreate2(nil, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return planner, nil
+}
+
+// SafeProposals converts the planned transactions of a Safe into Transaction Builder batches, one per
+// transaction, or a single batch of every transaction with batch. The Safe cannot create contracts
+// with a call, so the plan creates them through the CREATE2 factory, see NewSafePlanner.
+func (planner *Planner) SafeProposals(name string, batch bool) ([]*SafeProposal, error) {
+	if !planner.backend.safe {
+		return nil, fmt.Errorf("plan is not made for a Safe")
+	}
+
+	bundle := planner.Bundle()
+
+	var proposals []*SafeProposal
+	for _, planned := range bundle.Transactions {
+		tx := planned.Transaction
+		if tx.To() == nil {
+			return nil, fmt.Errorf("%s %s creates a contract, which a Safe can only do through a CREATE2 factory", planned.Contract, planned.Step)
+		}
+
+		builderTx := &SafeBuilderTx{
+			To:    tx.To().Hex(),
+			Value: tx.Value().String(),
+			Data:  hexutil.Encode(tx.Data()),
+		}
+		description := planned.Contract + " " + planned.Step
+		if batch && len(proposals) > 0 {
+			proposal := proposals[0]
+			proposal.Meta.Description += ", " + description
+			proposal.Transactions = append(proposal.Transactions, builderTx)
+			continue
+		}
+
+		proposals = append(proposals, &SafeProposal{
+			Version:   "1.0",
+			ChainID:   planner.chainID.String(),
+			CreatedAt: bundle.CreatedAt.UnixNano() / int64(time.Millisecond),
+			Meta: SafeProposalMeta{
+				Name:                   name,
+				Description:            description,
+				TxBuilderVersion:       TxBuilderVersion,
+				CreatedFromSafeAddress: planner.backend.from.Hex(),
+			},
+			Transactions: []*SafeBuilderTx{builderTx},
+		})
+	}
+
+	return proposals, nil
+}
+
+func (proposal *SafeProposal) Save(filename string) error {
+	data, err := json.MarshalIndent(proposal, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
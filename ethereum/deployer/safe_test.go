@@ -0,0 +1,214 @@
+package deployer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// label marks a jump destination, jump pushes its offset.
+type label string
+type jump string
+type push1 byte
+
+// assemble builds EVM code from opcodes, one byte pushes and labels.
+func assemble(items ...interface{}) []byte {
+	labels := make(map[label]int)
+	pc := 0
+	for _, item := range items {
+		switch item := item.(type) {
+		case label:
+			labels[item] = pc
+			pc++
+		case jump, push1:
+			pc += 2
+		case vm.OpCode:
+			pc++
+		}
+	}
+
+	var code []byte
+	for _, item := range items {
+		switch item := item.(type) {
+		case label:
+			code = append(code, byte(vm.JUMPDEST))
+		case jump:
+			code = append(code, byte(vm.PUSH1), byte(labels[label(item)]))
+		case push1:
+			code = append(code, byte(vm.PUSH1), byte(item))
+		case vm.OpCode:
+			code = append(code, byte(item))
+		}
+	}
+
+	return code
+}
+
+// bubbleRevert reverts with the return data of the failed call.
+var bubbleRevert = []interface{}{
+	vm.RETURNDATASIZE, push1(0), push1(0), vm.RETURNDATACOPY, vm.RETURNDATASIZE, push1(0), vm.REVERT,
+}
+
+// safeExecutorCode stands in for a Safe whose owners have signed: it calls with calldata of
+// to (20 bytes), value (32 bytes) and data.
+func safeExecutorCode() []byte {
+	items := []interface{}{
+		// data length, the data is copied to memory 0
+		push1(52), vm.CALLDATASIZE, vm.SUB,
+		vm.DUP1, push1(52), push1(0), vm.CALLDATACOPY,
+		push1(0), push1(0), vm.DUP3, push1(0),
+		push1(20), vm.CALLDATALOAD,
+		push1(0), vm.CALLDATALOAD, push1(96), vm.SHR,
+		vm.GAS, vm.CALL,
+		jump("ok"), vm.JUMPI,
+	}
+	items = append(items, bubbleRevert...)
+
+	return assemble(append(items, label("ok"), vm.STOP)...)
+}
+
+type safeChain struct {
+	backend  *SimulatedBackend
+	key      *ecdsa.PrivateKey
+	executor common.Address
+	safe     common.Address
+	gravity  string
+}
+
+func newSafeChain(t *testing.T) *safeChain {
+	keys, addresses := generateKeys(t, 1)
+	chain := &safeChain{
+		key:      keys[0],
+		executor: addresses[0],
+		safe:     common.HexToAddress("0x5afe000000000000000000000000000000005afe"),
+	}
+
+	alloc := core.GenesisAlloc{
+		chain.executor: {Balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)},
+		chain.safe:     {Balance: new(big.Int), Code: safeExecutorCode(), Nonce: 1},
+		common.HexToAddress(DefaultCreate2Factory): {Balance: new(big.Int), Code: common.FromHex(create2FactoryCode)},
+	}
+	chain.backend = &SimulatedBackend{SimulatedBackend: backends.NewSimulatedBackend(alloc, simulatedGasLimit)}
+
+	ethDeployer := NewEthDeployer(chain.backend, chain.transactor(t))
+	gravity, err := ethDeployer.DeployGravity([]string{chain.executor.Hex()}, 1, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.gravity = gravity
+
+	return chain
+}
+
+func (chain *safeChain) transactor(t *testing.T) *bind.TransactOpts {
+	transactor, err := bind.NewKeyedTransactorWithChainID(chain.key, big.NewInt(SimulatedChainID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return transactor
+}
+
+// execute runs the transactions of the proposals in order.
+func (chain *safeChain) execute(t *testing.T, proposals []*SafeProposal) {
+	contract := bind.NewBoundContract(chain.safe, abi.ABI{}, chain.backend, chain.backend, chain.backend)
+	for _, proposal := range proposals {
+		for _, builderTx := range proposal.Transactions {
+			value, ok := new(big.Int).SetString(builderTx.Value, 10)
+			if !ok {
+				t.Fatalf("invalid value %s", builderTx.Value)
+			}
+
+			calldata := append(common.HexToAddress(builderTx.To).Bytes(), common.LeftPadBytes(value.Bytes(), 32)...)
+			calldata = append(calldata, hexutil.MustDecode(builderTx.Data)...)
+			tx, err := contract.RawTransact(chain.transactor(t), calldata)
+			if err != nil {
+				t.Fatalf("%s: %v", proposal.Meta.Description, err)
+			}
+			receipt, err := chain.backend.TransactionReceipt(context.Background(), tx.Hash())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				t.Fatalf("%s failed", proposal.Meta.Description)
+			}
+		}
+	}
+}
+
+func testSafeProposals(t *testing.T, batch bool, proposals int) {
+	chain := newSafeChain(t)
+	ctx := context.Background()
+
+	planner, err := NewSafePlanner(chain.backend, chain.safe, big.NewInt(SimulatedChainID), ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oracles := []common.Address{chain.executor}
+	nebula, err := planner.Deployer().DeployNebula(chain.gravity, 2, oracles, 1, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscriber := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	_, err = planner.Deployer().Subscribe(common.HexToAddress(nebula), subscriber, 1, big.NewInt(0), ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	planned, err := planner.SafeProposals("test", batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(planned) != proposals {
+		t.Fatalf("expected %d proposals, got %d", proposals, len(planned))
+	}
+	for _, proposal := range planned {
+		if proposal.Version != "1.0" || proposal.ChainID != "1337" || proposal.Meta.CreatedFromSafeAddress != chain.safe.Hex() {
+			t.Errorf("unexpected proposal %+v", proposal)
+		}
+	}
+
+	chain.execute(t, planned)
+
+	code, err := chain.backend.CodeAt(ctx, common.HexToAddress(nebula), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) == 0 {
+		t.Fatalf("nebula is not created at the planned address %s", nebula)
+	}
+
+	subscriptions, err := NebulaSubscriptions(chain.backend, common.HexToAddress(nebula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subscriptions) != 1 {
+		t.Fatalf("expected 1 subscription, got %d", len(subscriptions))
+	}
+	if subscriptions[0].Owner != chain.safe || subscriptions[0].ContractAddress != subscriber {
+		t.Errorf("unexpected subscription %+v", subscriptions[0])
+	}
+	if subscriptions[0].ID != subscriptionID(chain.safe, subscriber, 1) {
+		t.Errorf("expected subscription id %s, got %s", subscriptionID(chain.safe, subscriber, 1).Hex(), subscriptions[0].ID.Hex())
+	}
+}
+
+func TestSafeProposals(t *testing.T) {
+	// the nebula library, the nebula and the subscription
+	testSafeProposals(t, false, 3)
+}
+
+func TestSafeProposalsBatch(t *testing.T) {
+	testSafeProposals(t, true, 1)
+}
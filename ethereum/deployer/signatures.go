@@ -0,0 +1,139 @@
+package deployer
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// UpdateMessageHash is the hash the consuls sign to move gravity to new consuls:
+// keccak256 of the packed addresses followed by the round.
+// Consuls sign the hash itself, without the Ethereum signed message prefix.
+func UpdateMessageHash(addresses []common.Address, round *big.Int) common.Hash {
+	return crypto.Keccak256Hash(packAddresses(addresses), math.U256Bytes(new(big.Int).Set(round)))
+}
+
+// OraclesMessageHash is the hash the consuls sign to move a nebula to new oracles:
+// keccak256 of the packed addresses. Unlike the consuls update it does not include the round.
+func OraclesMessageHash(oracles []common.Address) common.Hash {
+	return crypto.Keccak256Hash(packAddresses(oracles))
+}
+
+func packAddresses(addresses []common.Address) []byte {
+	var data []byte
+	for _, address := range addresses {
+		data = append(data, address.Bytes()...)
+	}
+
+	return data
+}
+
+// SignedUpdate is an update the consuls sign, such as ConsulsUpdate and OraclesUpdate.
+type SignedUpdate interface {
+	// Approvals returns the signatures assigned to the consuls and the number the update needs
+	Approvals() (*ConsulSignatures, int)
+	// CheckQuorum fails when the update cannot be submitted with the signatures
+	CheckQuorum() error
+}
+
+// ConsulSignatures are the signatures of an update message ordered by consul,
+// as the contracts check the signature at the index of every consul.
+// Missing signatures are left zero.
+type ConsulSignatures struct {
+	Hash    common.Hash
+	Consuls []common.Address
+	V       []uint8
+	R       [][32]byte
+	S       [][32]byte
+	// Signed marks the consuls with a valid signature
+	Signed []bool
+}
+
+// Count returns the number of consuls with a valid signature.
+func (cs *ConsulSignatures) Count() int {
+	var count int
+	for _, signed := range cs.Signed {
+		if signed {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Missing returns the consuls without a signature.
+func (cs *ConsulSignatures) Missing() []common.Address {
+	var missing []common.Address
+	for i, signed := range cs.Signed {
+		if !signed {
+			missing = append(missing, cs.Consuls[i])
+		}
+	}
+
+	return missing
+}
+
+func (cs *ConsulSignatures) checkQuorum(quorum int) error {
+	if cs.Count() < quorum {
+		return fmt.Errorf("%d of %d required consul signatures, missing: %s",
+			cs.Count(), quorum, formatAddresses(cs.Missing()))
+	}
+
+	return nil
+}
+
+// AssignSignatures recovers the signer of every 65 byte [R || S || V] signature and puts it
+// at the index of the consul. Malformed signatures and signatures of other keys are rejected,
+// repeated signatures of a consul are ignored.
+func AssignSignatures(hash common.Hash, consuls []common.Address, signatures [][]byte) (*ConsulSignatures, error) {
+	cs := &ConsulSignatures{
+		Hash:    hash,
+		Consuls: consuls,
+		V:       make([]uint8, len(consuls)),
+		R:       make([][32]byte, len(consuls)),
+		S:       make([][32]byte, len(consuls)),
+		Signed:  make([]bool, len(consuls)),
+	}
+
+	for i, signature := range signatures {
+		if len(signature) != crypto.SignatureLength {
+			return nil, fmt.Errorf("signature %d has %d bytes, expected %d", i+1, len(signature), crypto.SignatureLength)
+		}
+
+		sig := make([]byte, crypto.SignatureLength)
+		copy(sig, signature)
+		if sig[64] >= 27 {
+			sig[64] -= 27
+		}
+
+		publicKey, err := crypto.SigToPub(hash.Bytes(), sig)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i+1, err)
+		}
+		signer := crypto.PubkeyToAddress(*publicKey)
+
+		index := -1
+		for j, consul := range consuls {
+			if consul == signer {
+				index = j
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("signature %d is signed by %s, which is not a consul", i+1, signer.Hex())
+		}
+		if cs.Signed[index] {
+			continue
+		}
+
+		copy(cs.R[index][:], sig[:32])
+		copy(cs.S[index][:], sig[32:64])
+		cs.V[index] = sig[64] + 27
+		cs.Signed[index] = true
+	}
+
+	return cs, nil
+}
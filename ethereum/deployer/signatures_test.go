@@ -0,0 +1,102 @@
+package deployer
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func generateKeys(t *testing.T, count int) ([]*ecdsa.PrivateKey, []common.Address) {
+	var keys []*ecdsa.PrivateKey
+	var addresses []common.Address
+	for i := 0; i < count; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		addresses = append(addresses, crypto.PubkeyToAddress(key.PublicKey))
+	}
+
+	return keys, addresses
+}
+
+func TestUpdateMessageHash(t *testing.T) {
+	addresses := []common.Address{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+	}
+	round := big.NewInt(7)
+
+	expected := crypto.Keccak256Hash(addresses[0].Bytes(), addresses[1].Bytes(), common.LeftPadBytes(round.Bytes(), 32))
+	if hash := UpdateMessageHash(addresses, round); hash != expected {
+		t.Errorf("expected %s, got %s", expected.Hex(), hash.Hex())
+	}
+	if round.Int64() != 7 {
+		t.Errorf("round changed to %s", round)
+	}
+}
+
+func TestAssignSignatures(t *testing.T) {
+	keys, consuls := generateKeys(t, 3)
+	hash := UpdateMessageHash(consuls, big.NewInt(1))
+
+	var signatures [][]byte
+	for _, key := range []*ecdsa.PrivateKey{keys[2], keys[0], keys[2]} {
+		signature, err := crypto.Sign(hash.Bytes(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signatures = append(signatures, signature)
+	}
+	// Signatures with V of 27 or 28 are accepted as well
+	signatures[1][64] += 27
+
+	assigned, err := AssignSignatures(hash, consuls, signatures)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assigned.Count() != 2 {
+		t.Fatalf("expected 2 signatures, got %d", assigned.Count())
+	}
+	if missing := assigned.Missing(); len(missing) != 1 || missing[0] != consuls[1] {
+		t.Errorf("expected missing consul %s, got %v", consuls[1].Hex(), missing)
+	}
+
+	for _, i := range []int{0, 2} {
+		if assigned.V[i] != 27 && assigned.V[i] != 28 {
+			t.Errorf("consul %d: expected V of 27 or 28, got %d", i, assigned.V[i])
+		}
+
+		sig := append(append(assigned.R[i][:], assigned.S[i][:]...), assigned.V[i]-27)
+		publicKey, err := crypto.SigToPub(hash.Bytes(), sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if crypto.PubkeyToAddress(*publicKey) != consuls[i] {
+			t.Errorf("consul %d: signature is not at the consul index", i)
+		}
+	}
+}
+
+func TestAssignSignaturesNotConsul(t *testing.T) {
+	keys, _ := generateKeys(t, 1)
+	_, consuls := generateKeys(t, 2)
+	hash := UpdateMessageHash(consuls, big.NewInt(1))
+
+	signature, err := crypto.Sign(hash.Bytes(), keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := AssignSignatures(hash, consuls, [][]byte{signature}); err == nil {
+		t.Error("expected error for a signature of another key")
+	}
+	if _, err := AssignSignatures(hash, consuls, [][]byte{signature[:64]}); err == nil {
+		t.Error("expected error for a short signature")
+	}
+}
@@ -0,0 +1,328 @@
+package deployer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+
+	"github.com/Gravity-Tech/gateway/abi/ethereum/ibport"
+	"github.com/Gravity-Tech/gateway/abi/ethereum/luport"
+	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DeployState is persisted after every completed deployment step,
+// so a rerun continues from the first missing step instead of starting over.
+type DeployState struct {
+	// ChainID is the chain the state was started on, a rerun on another chain is refused
+	ChainID string `json:",omitempty"`
+	Ports   map[string]*PortState
+
+	filename string
+}
+
+// PortState holds the completed steps of a single port deployment.
+type PortState struct {
+	Contracts  []*ContractRecord
+	Subscribed bool
+	// Minted is set once the initial supply of the test token is minted
+	Minted bool
+
+	store *DeployState
+}
+
+// LoadDeployState reads the state file, a missing file gives an empty state.
+func LoadDeployState(filename string) (*DeployState, error) {
+	state := &DeployState{
+		Ports:    make(map[string]*PortState),
+		filename: filename,
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", filename, err)
+	}
+	if state.Ports == nil {
+		state.Ports = make(map[string]*PortState)
+	}
+
+	return state, nil
+}
+
+// CheckChain binds an empty state to the chain and fails for a state started on another chain.
+func (state *DeployState) CheckChain(chainID *big.Int) error {
+	if state.ChainID == "" {
+		state.ChainID = chainID.String()
+		return nil
+	}
+	if state.ChainID != chainID.String() {
+		return fmt.Errorf("state file %s belongs to chain %s, not %s", state.filename, state.ChainID, chainID)
+	}
+
+	return nil
+}
+
+func (state *DeployState) Save() error {
+	if state.filename == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(state.filename, data, 0644)
+}
+
+// Port returns the state of the named port, creating it if needed.
+func (state *DeployState) Port(name string) *PortState {
+	port, ok := state.Ports[name]
+	if !ok {
+		port = new(PortState)
+		state.Ports[name] = port
+	}
+	port.store = state
+
+	return port
+}
+
+func (state *PortState) Save() error {
+	if state.store == nil {
+		return nil
+	}
+
+	return state.store.Save()
+}
+
+func (state *PortState) contract(name string) *ContractRecord {
+	for _, record := range state.Contracts {
+		if record.Name == name {
+			return record
+		}
+	}
+
+	return nil
+}
+
+func (state *PortState) complete(record *ContractRecord) error {
+	state.Contracts = append(state.Contracts, record)
+
+	return state.Save()
+}
+
+func (deployer *EthDeployer) hasCode(address common.Address, ctx context.Context) (bool, error) {
+	code, err := deployer.ethClient.CodeAt(ctx, address, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return len(code) > 0, nil
+}
+
+// resumeContract returns the saved record of a completed step after checking the contract is deployed
+// with the same constructor arguments.
+func (deployer *EthDeployer) resumeContract(state *PortState, name string, args map[string]interface{},
+	ctx context.Context) (*ContractRecord, error) {
+
+	record := state.contract(name)
+	if record == nil {
+		return nil, nil
+	}
+
+	saved, err := json.Marshal(record.ConstructorArgs)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := normalizeArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(saved, expected) {
+		return nil, fmt.Errorf("%s %s from the state file was deployed with %s, expected %s", name, record.Address, saved, expected)
+	}
+
+	deployed, err := deployer.hasCode(common.HexToAddress(record.Address), ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !deployed {
+		return nil, fmt.Errorf("%s %s from the state file is not deployed", name, record.Address)
+	}
+
+	fmt.Printf("%s already deployed: %s \n", name, record.Address)
+	deployer.contracts = append(deployer.contracts, record)
+
+	return record, nil
+}
+
+// normalizeArgs encodes constructor arguments the way they read back from a state file.
+func normalizeArgs(args map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded map[string]interface{}
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(decoded)
+}
+
+func nebulaArgs(gravityAddress common.Address, dataType int, oracles []common.Address, bftCoefficient int) map[string]interface{} {
+	return map[string]interface{}{
+		"dataType":        dataType,
+		"gravityContract": gravityAddress.Hex(),
+		"oracles":         oracles,
+		"bftValue":        bftCoefficient,
+	}
+}
+
+func portArgs(nebulaAddress common.Address, erc20Address common.Address) map[string]interface{} {
+	return map[string]interface{}{
+		"nebula":       nebulaAddress,
+		"tokenAddress": erc20Address,
+	}
+}
+
+// resumeNebula resumes the nebula of the state after reading its constructor arguments on-chain.
+func (deployer *EthDeployer) resumeNebula(state *PortState, gravityAddress common.Address, dataType int,
+	oracles []common.Address, bftCoefficient int, ctx context.Context) (*ContractRecord, error) {
+
+	record, err := deployer.resumeContract(state, "Nebula", nebulaArgs(gravityAddress, dataType, oracles, bftCoefficient), ctx)
+	if err != nil || record == nil {
+		return record, err
+	}
+
+	nebula, err := ethereum.NewNebula(common.HexToAddress(record.Address), deployer.ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+
+	gravityContract, err := nebula.GravityContract(opts)
+	if err != nil {
+		return nil, err
+	}
+	if gravityContract != gravityAddress {
+		return nil, fmt.Errorf("nebula %s uses gravity %s, expected %s", record.Address, gravityContract.Hex(), gravityAddress.Hex())
+	}
+
+	nebulaDataType, err := nebula.DataType(opts)
+	if err != nil {
+		return nil, err
+	}
+	if int(nebulaDataType) != dataType {
+		return nil, fmt.Errorf("nebula %s has data type %s, expected %s", record.Address,
+			ExtractorType(nebulaDataType), ExtractorType(dataType))
+	}
+
+	bftValue, err := nebula.BftValue(opts)
+	if err != nil {
+		return nil, err
+	}
+	if bftValue.Cmp(big.NewInt(int64(bftCoefficient))) != 0 {
+		return nil, fmt.Errorf("nebula %s has bft value %s, expected %d", record.Address, bftValue, bftCoefficient)
+	}
+
+	nebulaOracles, err := nebula.GetOracles(opts)
+	if err != nil {
+		return nil, err
+	}
+	if formatAddresses(nebulaOracles) != formatAddresses(oracles) {
+		return nil, fmt.Errorf("nebula %s has oracles %s, expected %s", record.Address,
+			formatAddresses(nebulaOracles), formatAddresses(oracles))
+	}
+
+	return record, nil
+}
+
+// resumePort resumes the port of the state after reading its nebula and token on-chain.
+func (deployer *EthDeployer) resumePort(state *PortState, portType PortType, nebulaAddress common.Address,
+	erc20Address common.Address, ctx context.Context) (*ContractRecord, error) {
+
+	record, err := deployer.resumeContract(state, portType.ContractName(), portArgs(nebulaAddress, erc20Address), ctx)
+	if err != nil || record == nil {
+		return record, err
+	}
+
+	portNebula, portToken, err := deployer.portContracts(portType, common.HexToAddress(record.Address), ctx)
+	if err != nil {
+		return nil, err
+	}
+	if portNebula != nebulaAddress {
+		return nil, fmt.Errorf("port %s uses nebula %s, expected %s", record.Address, portNebula.Hex(), nebulaAddress.Hex())
+	}
+	if portToken != erc20Address {
+		return nil, fmt.Errorf("port %s uses token %s, expected %s", record.Address, portToken.Hex(), erc20Address.Hex())
+	}
+
+	return record, nil
+}
+
+// portContracts reads the nebula and the token of the port.
+func (deployer *EthDeployer) portContracts(portType PortType, portAddress common.Address,
+	ctx context.Context) (common.Address, common.Address, error) {
+
+	opts := &bind.CallOpts{Context: ctx}
+	var nebula, token common.Address
+	switch portType {
+	case IBPort:
+		port, err := ibport.NewIBPort(portAddress, deployer.ethClient)
+		if err != nil {
+			return nebula, token, err
+		}
+		nebula, err = port.Nebula(opts)
+		if err != nil {
+			return nebula, token, err
+		}
+		token, err = port.TokenAddress(opts)
+		return nebula, token, err
+	case LUPort:
+		port, err := luport.NewLUPort(portAddress, deployer.ethClient)
+		if err != nil {
+			return nebula, token, err
+		}
+		nebula, err = port.Nebula(opts)
+		if err != nil {
+			return nebula, token, err
+		}
+		token, err = port.TokenAddress(opts)
+		return nebula, token, err
+	}
+
+	return nebula, token, fmt.Errorf("unknown port type %d", portType)
+}
+
+// contractSubscription returns the subscription of the contract to the nebula, nil when it is not subscribed.
+func contractSubscription(nebula *ethereum.Nebula, contractAddress common.Address) (*Subscription, error) {
+	subscriptions, err := nebulaSubscriptions(nebula)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.ContractAddress == contractAddress {
+			return subscription, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -17,6 +17,19 @@ const (
 	BytesType
 )
 
+func (t ExtractorType) String() string {
+	switch t {
+	case Int64Type:
+		return "int64"
+	case StringType:
+		return "string"
+	case BytesType:
+		return "bytes"
+	}
+
+	return ""
+}
+
 func ParseExtractorType(extractorType string) (ExtractorType, error) {
 	switch strings.ToLower(extractorType) {
 	case "int64":
@@ -0,0 +1,188 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Gravity-Tech/gateway/abi/ethereum/ibport"
+	"github.com/Gravity-Tech/gateway/abi/ethereum/luport"
+	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ExpectedPort is the configuration a deployed nebula and port are checked against.
+type ExpectedPort struct {
+	// NebulaOnly checks a nebula without a port, PortType, PortAddress,
+	// TokenAddress and Subscription are not used
+	NebulaOnly     bool
+	PortType       PortType
+	PortAddress    common.Address
+	NebulaAddress  common.Address
+	GravityAddress common.Address
+	TokenAddress   common.Address
+	Oracles        []common.Address
+	BftCoefficient int
+	DataType       ExtractorType
+	// Subscription of the port to the nebula, DefaultSubscription when nil
+	Subscription *Subscription
+}
+
+// Mismatch is a value read on-chain that differs from the expected one.
+type Mismatch struct {
+	Contract string
+	Field    string
+	Expected string
+	Actual   string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s %s: expected %s, got %s", m.Contract, m.Field, m.Expected, m.Actual)
+}
+
+type portVerifier struct {
+	mismatches []Mismatch
+}
+
+func (v *portVerifier) check(contract string, field string, expected string, actual string) {
+	if expected != actual {
+		v.mismatches = append(v.mismatches, Mismatch{
+			Contract: contract,
+			Field:    field,
+			Expected: expected,
+			Actual:   actual,
+		})
+	}
+}
+
+// VerifyPort reads the nebula and port settings on-chain and returns every mismatch with the expected configuration.
+func VerifyPort(backend Backend, expected *ExpectedPort, ctx context.Context) ([]Mismatch, error) {
+	v := new(portVerifier)
+
+	type contract struct {
+		name    string
+		address common.Address
+	}
+	contracts := []contract{{"Nebula", expected.NebulaAddress}}
+	if !expected.NebulaOnly {
+		contracts = append(contracts, contract{expected.PortType.ContractName(), expected.PortAddress})
+	}
+	for _, contract := range contracts {
+		code, err := backend.CodeAt(ctx, contract.address, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(code) == 0 {
+			v.check(contract.name, "code", "deployed", "empty")
+		}
+	}
+	if len(v.mismatches) > 0 {
+		return v.mismatches, nil
+	}
+
+	nebula, err := ethereum.NewNebula(expected.NebulaAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+
+	oracles, err := nebula.GetOracles(opts)
+	if err != nil {
+		return nil, err
+	}
+	v.check("Nebula", "oracles", formatAddresses(expected.Oracles), formatAddresses(oracles))
+
+	bftValue, err := nebula.BftValue(opts)
+	if err != nil {
+		return nil, err
+	}
+	v.check("Nebula", "bftValue", fmt.Sprint(expected.BftCoefficient), bftValue.String())
+
+	gravityContract, err := nebula.GravityContract(opts)
+	if err != nil {
+		return nil, err
+	}
+	v.check("Nebula", "gravityContract", expected.GravityAddress.Hex(), gravityContract.Hex())
+
+	dataType, err := nebula.DataType(opts)
+	if err != nil {
+		return nil, err
+	}
+	v.check("Nebula", "dataType", expected.DataType.String(), ExtractorType(dataType).String())
+
+	if expected.NebulaOnly {
+		return v.mismatches, nil
+	}
+
+	subscription, err := contractSubscription(nebula, expected.PortAddress)
+	if err != nil {
+		return nil, err
+	}
+	v.check("Nebula", "subscriber", expected.PortAddress.Hex(), subscriberStatus(subscription != nil, expected.PortAddress))
+	if subscription != nil {
+		expectedSubscription := expected.Subscription
+		if expectedSubscription == nil {
+			expectedSubscription = DefaultSubscription()
+		}
+		v.check("Nebula", "minConfirmations", fmt.Sprint(expectedSubscription.MinConfirmations), fmt.Sprint(subscription.MinConfirmations))
+		v.check("Nebula", "reward", expectedSubscription.Reward.String(), subscription.Reward.String())
+	}
+
+	var portNebula, portToken common.Address
+	switch expected.PortType {
+	case IBPort:
+		port, err := ibport.NewIBPort(expected.PortAddress, backend)
+		if err != nil {
+			return nil, err
+		}
+		portNebula, err = port.Nebula(opts)
+		if err != nil {
+			return nil, err
+		}
+		portToken, err = port.TokenAddress(opts)
+		if err != nil {
+			return nil, err
+		}
+	case LUPort:
+		port, err := luport.NewLUPort(expected.PortAddress, backend)
+		if err != nil {
+			return nil, err
+		}
+		portNebula, err = port.Nebula(opts)
+		if err != nil {
+			return nil, err
+		}
+		portToken, err = port.TokenAddress(opts)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown port type %d", expected.PortType)
+	}
+
+	portName := expected.PortType.ContractName()
+	v.check(portName, "nebula", expected.NebulaAddress.Hex(), portNebula.Hex())
+	v.check(portName, "tokenAddress", expected.TokenAddress.Hex(), portToken.Hex())
+
+	return v.mismatches, nil
+}
+
+func formatAddresses(addresses []common.Address) string {
+	var formatted []string
+	for _, address := range addresses {
+		formatted = append(formatted, address.Hex())
+	}
+
+	return "[" + strings.Join(formatted, ",") + "]"
+}
+
+func subscriberStatus(subscribed bool, address common.Address) string {
+	if subscribed {
+		return address.Hex()
+	}
+
+	return "not subscribed"
+}
@@ -0,0 +1,85 @@
+package deployer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestVerifyPort(t *testing.T) {
+	chain := newSafeChain(t)
+	ctx := context.Background()
+	ethDeployer := NewEthDeployer(chain.backend, chain.transactor(t))
+
+	oracles := []common.Address{chain.executor}
+	token := "0x1111111111111111111111111111111111111111"
+	subscription := &Subscription{MinConfirmations: 2, Reward: big.NewInt(10)}
+	port, err := ethDeployer.DeployPortPipelined(chain.gravity, int(BytesType), token, oracles, 1, IBPort, subscription, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &ExpectedPort{
+		PortType:       IBPort,
+		PortAddress:    common.HexToAddress(port.PortAddress),
+		NebulaAddress:  common.HexToAddress(port.NebulaAddress),
+		GravityAddress: common.HexToAddress(chain.gravity),
+		TokenAddress:   common.HexToAddress(token),
+		Oracles:        oracles,
+		BftCoefficient: 1,
+		DataType:       BytesType,
+		Subscription:   subscription,
+	}
+	mismatches, err := VerifyPort(chain.backend, expected, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("expected no mismatches, got %+v", mismatches)
+	}
+
+	expected.Subscription = DefaultSubscription()
+	mismatches, err = VerifyPort(chain.backend, expected, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []string{"minConfirmations", "reward"}
+	if len(mismatches) != len(fields) {
+		t.Fatalf("expected %d mismatches, got %+v", len(fields), mismatches)
+	}
+	for i, mismatch := range mismatches {
+		if mismatch.Field != fields[i] {
+			t.Errorf("expected a %s mismatch, got %+v", fields[i], mismatch)
+		}
+	}
+}
+
+func TestVerifyNebula(t *testing.T) {
+	chain := newSafeChain(t)
+	ctx := context.Background()
+	ethDeployer := NewEthDeployer(chain.backend, chain.transactor(t))
+
+	oracles := []common.Address{chain.executor}
+	nebula, err := ethDeployer.DeployNebula(chain.gravity, int(Int64Type), oracles, 1, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &ExpectedPort{
+		NebulaOnly:     true,
+		NebulaAddress:  common.HexToAddress(nebula),
+		GravityAddress: common.HexToAddress(chain.gravity),
+		Oracles:        oracles,
+		BftCoefficient: 1,
+		DataType:       StringType,
+	}
+	mismatches, err := VerifyPort(chain.backend, expected, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 1 || mismatches[0].Field != "dataType" {
+		t.Errorf("expected a dataType mismatch, got %+v", mismatches)
+	}
+}
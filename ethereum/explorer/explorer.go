@@ -0,0 +1,216 @@
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SingleFileFormat   = "solidity-single-file"
+	StandardJsonFormat = "solidity-standard-json-input"
+
+	DefaultPollInterval = 5 * time.Second
+	// DefaultMaxAttempts of status checks wait five minutes with the default poll interval
+	DefaultMaxAttempts = 60
+	// submitRetries covers explorers that have not indexed a freshly deployed contract yet
+	submitRetries = 5
+)
+
+var (
+	ErrVerificationFailed = errors.New("verification failed")
+	// ErrVerificationPending is returned when the verification is still pending after MaxAttempts status checks
+	ErrVerificationPending = errors.New("verification still pending")
+)
+
+// Client talks to an Etherscan compatible contract verification API,
+// such as Etherscan, BscScan or FtmScan.
+type Client struct {
+	apiUrl       string
+	apiKey       string
+	httpClient   *http.Client
+	PollInterval time.Duration
+	MaxAttempts  int
+}
+
+type VerifyRequest struct {
+	ContractAddress string
+	SourceCode      string
+	// CodeFormat is SingleFileFormat or StandardJsonFormat
+	CodeFormat       string
+	ContractName     string
+	CompilerVersion  string
+	OptimizationUsed bool
+	Runs             int
+	// ConstructorArguments are ABI encoded and hex formatted
+	ConstructorArguments string
+	EVMVersion           string
+	LicenseType          int
+}
+
+type response struct {
+	Status  string
+	Message string
+	Result  string
+}
+
+func NewClient(apiUrl string, apiKey string) *Client {
+	return &Client{
+		apiUrl:       apiUrl,
+		apiKey:       apiKey,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		PollInterval: DefaultPollInterval,
+		MaxAttempts:  DefaultMaxAttempts,
+	}
+}
+
+// Verify submits the source code and polls until the verification finishes,
+// at most MaxAttempts times.
+func (client *Client) Verify(ctx context.Context, request *VerifyRequest) error {
+	var guid string
+	var err error
+	for i := 0; i < submitRetries; i++ {
+		guid, err = client.Submit(ctx, request)
+		if err == nil || !strings.Contains(strings.ToLower(err.Error()), "unable to locate contractcode") {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(client.PollInterval):
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < client.MaxAttempts; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(client.PollInterval):
+		}
+
+		done, err := client.CheckStatus(ctx, guid)
+		if err != nil || done {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w after %d status checks: %s", ErrVerificationPending, client.MaxAttempts, guid)
+}
+
+// Submit sends the source code for verification and returns the GUID of the request.
+func (client *Client) Submit(ctx context.Context, request *VerifyRequest) (string, error) {
+	codeFormat := request.CodeFormat
+	if codeFormat == "" {
+		codeFormat = SingleFileFormat
+	}
+
+	optimizationUsed := "0"
+	if request.OptimizationUsed {
+		optimizationUsed = "1"
+	}
+
+	form := url.Values{
+		"apikey":           {client.apiKey},
+		"module":           {"contract"},
+		"action":           {"verifysourcecode"},
+		"contractaddress":  {request.ContractAddress},
+		"sourceCode":       {request.SourceCode},
+		"codeformat":       {codeFormat},
+		"contractname":     {request.ContractName},
+		"compilerversion":  {request.CompilerVersion},
+		"optimizationUsed": {optimizationUsed},
+		"runs":             {strconv.Itoa(request.Runs)},
+		// the misspelling is part of the API
+		"constructorArguements": {strings.TrimPrefix(request.ConstructorArguments, "0x")},
+	}
+	if request.EVMVersion != "" {
+		form.Set("evmversion", request.EVMVersion)
+	}
+	if request.LicenseType != 0 {
+		form.Set("licenseType", strconv.Itoa(request.LicenseType))
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, client.apiUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	result, err := client.do(httpRequest)
+	if err != nil {
+		return "", err
+	}
+	if result.Status != "1" {
+		if strings.Contains(strings.ToLower(result.Result), "already verified") {
+			return "", nil
+		}
+		return "", fmt.Errorf("submit verification: %s: %s", result.Message, result.Result)
+	}
+
+	return result.Result, nil
+}
+
+// CheckStatus reports whether the verification request has finished.
+// A failed verification is returned as ErrVerificationFailed.
+func (client *Client) CheckStatus(ctx context.Context, guid string) (bool, error) {
+	if guid == "" {
+		return true, nil
+	}
+
+	query := url.Values{
+		"apikey": {client.apiKey},
+		"module": {"contract"},
+		"action": {"checkverifystatus"},
+		"guid":   {guid},
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, client.apiUrl+"?"+query.Encode(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := client.do(httpRequest)
+	if err != nil {
+		return false, err
+	}
+
+	status := strings.ToLower(result.Result)
+	switch {
+	case strings.Contains(status, "pending"):
+		return false, nil
+	case result.Status == "1", strings.Contains(status, "already verified"):
+		return true, nil
+	default:
+		return true, fmt.Errorf("%w: %s", ErrVerificationFailed, result.Result)
+	}
+}
+
+func (client *Client) do(httpRequest *http.Request) (*response, error) {
+	httpResponse, err := client.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("explorer api returned %s", httpResponse.Status)
+	}
+
+	result := new(response)
+	err = json.NewDecoder(httpResponse.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
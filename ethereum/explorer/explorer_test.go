@@ -0,0 +1,124 @@
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServer answers like an explorer API. The handler runs outside the test goroutine,
+// so it records the errors and the test reports them with check.
+type testServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []string
+	errs     []error
+}
+
+func newTestServer(t *testing.T, statuses []string) *testServer {
+	server := &testServer{statuses: statuses}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	t.Cleanup(func() {
+		server.Close()
+		server.check(t)
+	})
+
+	return server
+}
+
+func (server *testServer) fail(w http.ResponseWriter, format string, args ...interface{}) {
+	server.mu.Lock()
+	server.errs = append(server.errs, fmt.Errorf(format, args...))
+	server.mu.Unlock()
+
+	http.Error(w, fmt.Sprintf(format, args...), http.StatusBadRequest)
+}
+
+func (server *testServer) handle(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		server.fail(w, "parse form: %v", err)
+		return
+	}
+
+	switch r.Form.Get("action") {
+	case "verifysourcecode":
+		if r.Form.Get("constructorArguements") != "abcd" {
+			server.fail(w, "unexpected constructor arguments: %s", r.Form.Get("constructorArguements"))
+			return
+		}
+		json.NewEncoder(w).Encode(response{Status: "1", Message: "OK", Result: "guid"})
+	case "checkverifystatus":
+		server.mu.Lock()
+		if len(server.statuses) == 0 {
+			server.mu.Unlock()
+			server.fail(w, "unexpected status check")
+			return
+		}
+		status := server.statuses[0]
+		server.statuses = server.statuses[1:]
+		server.mu.Unlock()
+
+		code := "0"
+		if status == "Pass - Verified" {
+			code = "1"
+		}
+		json.NewEncoder(w).Encode(response{Status: code, Message: "OK", Result: status})
+	default:
+		server.fail(w, "unexpected action: %s", r.Form.Get("action"))
+	}
+}
+
+// check reports the errors of the handler.
+func (server *testServer) check(t *testing.T) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	for _, err := range server.errs {
+		t.Error(err)
+	}
+	server.errs = nil
+}
+
+func TestVerify(t *testing.T) {
+	server := newTestServer(t, []string{"Pending in queue", "Pass - Verified"})
+
+	client := NewClient(server.URL, "key")
+	client.PollInterval = time.Millisecond
+
+	err := client.Verify(context.Background(), &VerifyRequest{ConstructorArguments: "0xabcd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyFailed(t *testing.T) {
+	server := newTestServer(t, []string{"Fail - Unable to verify"})
+
+	client := NewClient(server.URL, "key")
+	client.PollInterval = time.Millisecond
+
+	err := client.Verify(context.Background(), &VerifyRequest{ConstructorArguments: "abcd"})
+	if !errors.Is(err, ErrVerificationFailed) {
+		t.Fatalf("expected verification failure, got %v", err)
+	}
+}
+
+func TestVerifyPending(t *testing.T) {
+	server := newTestServer(t, []string{"Pending in queue", "Pending in queue", "Pending in queue"})
+
+	client := NewClient(server.URL, "key")
+	client.PollInterval = time.Millisecond
+	client.MaxAttempts = 3
+
+	err := client.Verify(context.Background(), &VerifyRequest{ConstructorArguments: "abcd"})
+	if !errors.Is(err, ErrVerificationPending) {
+		t.Fatalf("expected a pending verification, got %v", err)
+	}
+}
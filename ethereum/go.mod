@@ -5,7 +5,7 @@ go 1.16
 require (
 	github.com/Gravity-Tech/gateway v0.0.0-20210320192720-efe55fee02c7
 	github.com/Gravity-Tech/gravity-core v1.0.2-0.20210406142321-b6e45813f6de
-	github.com/ethereum/go-ethereum v1.10.0
+	github.com/ethereum/go-ethereum v1.10.8
 	github.com/urfave/cli/v2 v2.2.0
 	golang.org/x/crypto v0.0.0-20210220033148-5ea612d1eb83 // indirect
 	golang.org/x/sys v0.0.0-20210228012217-479acdf4ea46 // indirect
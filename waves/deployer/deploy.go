@@ -3,6 +3,7 @@ package deployer
 import (
 	"context"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +17,9 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/proto"
 )
 
+// ContractFee is the fee in wavelets paid for every set script and data transaction.
+const ContractFee = 10000000
+
 func DeployGravityWaves(
 	client *wavesClient.Client,
 	helper wavesHelper.ClientHelper,
@@ -25,21 +29,27 @@ func DeployGravityWaves(
 	chainId byte,
 	secret wavesCrypto.SecretKey,
 	ctx context.Context,
-) error {
+) (*ContractRecord, error) {
 	id, err := DeployWavesContract(client, gravityScript, chainId, secret, ctx)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = <-helper.WaitTx(id, ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	log.Println("gravity tx", id)
 
-	id, err = DataWavesContract(client, chainId, secret, proto.DataEntries{
+	record, err := newContractRecord("Gravity", chainId, secret)
+	if err != nil {
+		return nil, err
+	}
+	record.Transactions = append(record.Transactions, newTxRecord("set-script", id))
+
+	entries := proto.DataEntries{
 		&proto.StringDataEntry{
 			Key:   "consuls_0",
 			Value: strings.Join(consulsPubKeys, ","),
@@ -52,35 +62,48 @@ func DeployGravityWaves(
 			Key:   "bft_coefficient",
 			Value: bftValue,
 		},
-	}, ctx)
+	}
+	id, err = DataWavesContract(client, chainId, secret, entries, ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = <-helper.WaitTx(id, ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	err = record.setDataEntries(entries)
+	if err != nil {
+		return nil, err
 	}
+	record.Transactions = append(record.Transactions, newTxRecord("data", id))
 
-	return nil
+	return record, nil
 }
 
 func DeployNebulaWaves(client *wavesClient.Client, helper wavesHelper.ClientHelper, nebulaScript []byte, gravityAddress string, subscriberAddress string,
-	oracles []string, bftValue int64, dataType contracts.ExtractorType, chainId byte, secret wavesCrypto.SecretKey, ctx context.Context) error {
+	oracles []string, bftValue int64, dataType contracts.ExtractorType, chainId byte, secret wavesCrypto.SecretKey, ctx context.Context) (*ContractRecord, error) {
 
 	id, err := DeployWavesContract(client, nebulaScript, chainId, secret, ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = <-helper.WaitTx(id, ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	log.Println("nebula tx", id)
 
-	id, err = DataWavesContract(client, chainId, secret, proto.DataEntries{
+	record, err := newContractRecord("Nebula", chainId, secret)
+	if err != nil {
+		return nil, err
+	}
+	record.Transactions = append(record.Transactions, newTxRecord("set-script", id))
+
+	entries := proto.DataEntries{
 		&proto.StringDataEntry{
 			Key:   "oracles",
 			Value: strings.Join(oracles, ","),
@@ -109,17 +132,24 @@ func DeployNebulaWaves(client *wavesClient.Client, helper wavesHelper.ClientHelp
 			Key:   "type",
 			Value: int64(dataType),
 		},
-	}, ctx)
+	}
+	id, err = DataWavesContract(client, chainId, secret, entries, ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = <-helper.WaitTx(id, ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	err = record.setDataEntries(entries)
+	if err != nil {
+		return nil, err
 	}
+	record.Transactions = append(record.Transactions, newTxRecord("data", id))
 
-	return nil
+	return record, nil
 }
 // Subscriber
 func DeploySubWaves(
@@ -131,19 +161,25 @@ func DeploySubWaves(
 	chainId byte,
 	secret wavesCrypto.SecretKey,
 	ctx context.Context,
-) error {
+) (*ContractRecord, error) {
 	id, err := DeployWavesContract(client, subScript, chainId, secret, ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Script deployment
 	err = <-helper.WaitTx(id, ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	id, err = DataWavesContract(client, chainId, secret, proto.DataEntries{
+	record, err := newContractRecord("Subscriber", chainId, secret)
+	if err != nil {
+		return nil, err
+	}
+	record.Transactions = append(record.Transactions, newTxRecord("set-script", id))
+
+	entries := proto.DataEntries{
 		&proto.StringDataEntry{
             Key:   "nebula_address",
             Value: nebulaAddress,
@@ -156,18 +192,45 @@ func DeploySubWaves(
             Key:   "type",
             Value: 2, // byte type
         },
-    }, ctx)
+    }
+	id, err = DataWavesContract(client, chainId, secret, entries, ctx)
 
     if err != nil {
-        return err
+        return nil, err
     }
 
     err = <-helper.WaitTx(id, ctx)
     if err != nil {
-        return err
+        return nil, err
     }
 
-	return nil
+	err = record.setDataEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+	record.Transactions = append(record.Transactions, newTxRecord("data", id))
+
+	return record, nil
+}
+
+func newContractRecord(name string, chainId byte, secret wavesCrypto.SecretKey) (*ContractRecord, error) {
+	address, err := proto.NewAddressFromPublicKey(chainId, wavesCrypto.GeneratePublicKey(secret))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContractRecord{
+		Name:    name,
+		Address: address.String(),
+	}, nil
+}
+
+func newTxRecord(step string, id string) *TxRecord {
+	return &TxRecord{
+		Step:   step,
+		TxHash: id,
+		Fee:    strconv.FormatUint(ContractFee, 10),
+	}
 }
 
 func DeployWavesContract(client *wavesClient.Client, contactScript []byte, chainId byte, secret wavesCrypto.SecretKey, ctx context.Context) (string, error) {
@@ -177,7 +240,7 @@ func DeployWavesContract(client *wavesClient.Client, contactScript []byte, chain
 		SenderPK:  wavesCrypto.GeneratePublicKey(secret),
 		ChainID:   chainId,
 		Script:    contactScript,
-		Fee:       10000000,
+		Fee:       ContractFee,
 		Timestamp: wavesClient.NewTimestampFromTime(time.Now()),
 	}
 	err := tx.Sign(chainId, secret)
@@ -199,7 +262,7 @@ func DataWavesContract(client *wavesClient.Client, chainId byte, secret wavesCry
 		Version:   1,
 		SenderPK:  wavesCrypto.GeneratePublicKey(secret),
 		Entries:   dataEntries,
-		Fee:       10000000,
+		Fee:       ContractFee,
 		Timestamp: wavesClient.NewTimestampFromTime(time.Now()),
 	}
 
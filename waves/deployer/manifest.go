@@ -0,0 +1,97 @@
+package deployer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// Manifest is the machine-readable record of a deployment run.
+// The ethereum deployer writes the same format.
+type Manifest struct {
+	ChainID   string
+	Deployer  string
+	CreatedAt time.Time
+	Contracts []*ContractRecord
+}
+
+type ContractRecord struct {
+	Name string
+	// Port is the name of the port the contract was deployed for
+	Port            string `json:",omitempty"`
+	PortType        string `json:",omitempty"`
+	Address         string
+	ConstructorArgs map[string]interface{} `json:",omitempty"`
+	// ConstructorData holds the encoded constructor arguments, ABI encoded on ethereum
+	// and the binary data entries set on the contract account on waves
+	ConstructorData string `json:",omitempty"`
+	Transactions    []*TxRecord
+}
+
+type TxRecord struct {
+	Step        string
+	TxHash      string
+	BlockNumber uint64 `json:",omitempty"`
+	GasUsed     uint64 `json:",omitempty"`
+	Fee         string `json:",omitempty"`
+}
+
+func (manifest *Manifest) Save(filename string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func LoadManifest(filename string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := new(Manifest)
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// setDataEntries records the data entries set on the contract account as its constructor arguments.
+func (record *ContractRecord) setDataEntries(entries proto.DataEntries) error {
+	var data []byte
+	for _, entry := range entries {
+		entryData, err := entry.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		data = append(data, entryData...)
+	}
+
+	record.ConstructorArgs = DataEntriesArgs(entries)
+	record.ConstructorData = "0x" + hex.EncodeToString(data)
+
+	return nil
+}
+
+// DataEntriesArgs converts data entries set on a contract account into manifest arguments.
+func DataEntriesArgs(entries proto.DataEntries) map[string]interface{} {
+	args := make(map[string]interface{})
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case *proto.StringDataEntry:
+			args[e.Key] = e.Value
+		case *proto.IntegerDataEntry:
+			args[e.Key] = e.Value
+		case *proto.BooleanDataEntry:
+			args[e.Key] = e.Value
+		}
+	}
+
+	return args
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/Gravity-Tech/gateway-deployer/waves/helper"
 	"os"
+	"strconv"
 	"time"
 
 	wavesCrypto "github.com/wavesplatform/go-lib-crypto"
@@ -25,11 +26,12 @@ const (
 	DeployOperation = "deploy"
 )
 
-var operation, configFile string
+var operation, configFile, manifestFile string
 
 func init() {
 	flag.StringVar(&operation, "operation", DeployOperation, "What action to perform")
 	flag.StringVar(&configFile, "config", "config.json", "Config file to read from")
+	flag.StringVar(&manifestFile, "manifest", "waves-manifest.json", "Deployment manifest file to write")
 	flag.Parse()
 }
 
@@ -106,6 +108,18 @@ func Deploy() (*helper.DeploymentConfig, error) {
 		return nil, err
 	}
 
+	distributorAddress, err := proto.NewAddressFromPublicKey(cfg.ChainId, crypto.GeneratePublicKey(distributionSeed))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &deployer.Manifest{
+		ChainID:   strconv.Itoa(int(cfg.ChainId)),
+		Deployer:  distributorAddress.String(),
+		CreatedAt: time.Now().UTC(),
+	}
+	defer saveManifest(manifest)
+
 	nebulaAddressRecipient, err := proto.NewRecipientFromString(testConfig.Nebula.Address)
 	if err != nil {
 		return nil, err
@@ -152,17 +166,34 @@ func Deploy() (*helper.DeploymentConfig, error) {
 		consulsString = append(consulsString, v)
 	}
 
-	err = deployer.DeploySubWaves(testConfig.Client, testConfig.Helper, subScript, nebulaAddressRecipient.String(), cfg.AssetID, cfg.ChainId, testConfig.Sub.Secret, testConfig.Ctx)
+	subRecord, err := deployer.DeploySubWaves(testConfig.Client, testConfig.Helper, subScript, nebulaAddressRecipient.String(), cfg.AssetID, cfg.ChainId, testConfig.Sub.Secret, testConfig.Ctx)
 	if err != nil {
 		return nil, err
 	}
+	manifest.Contracts = append(manifest.Contracts, subRecord)
 
 	oraclesString := consulsString[:]
-	err = deployer.DeployNebulaWaves(testConfig.Client, testConfig.Helper, nebulaScript, cfg.ExistingGravityAddress,
+	nebulaRecord, err := deployer.DeployNebulaWaves(testConfig.Client, testConfig.Helper, nebulaScript, cfg.ExistingGravityAddress,
 		testConfig.Sub.Address, oraclesString, cfg.BftValue, contracts.BytesType, cfg.ChainId, testConfig.Nebula.Secret, testConfig.Ctx)
 	if err != nil {
 		return nil, err
 	}
+	manifest.Contracts = append(manifest.Contracts, nebulaRecord)
 
 	return &testConfig, nil
 }
+
+// saveManifest writes the manifest even when the deployment fails part way.
+func saveManifest(manifest *deployer.Manifest) {
+	if manifestFile == "" {
+		return
+	}
+
+	err := manifest.Save(manifestFile)
+	if err != nil {
+		fmt.Printf("Failed to save manifest: %v \n", err)
+		return
+	}
+
+	fmt.Printf("Manifest: %s \n", manifestFile)
+}
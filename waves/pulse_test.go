@@ -190,12 +190,12 @@ func initTests() (*helper.TestPulseConfig, error) {
 	for _, v := range testConfig.Consuls {
 		consulsString = append(consulsString, v.Address)
 	}
-	err = deployer.DeployGravityWaves(testConfig.Client, testConfig.Helper, gravityScript, consulsString, BftValue, cfg.ChainId, testConfig.Gravity.Secret, testConfig.Ctx)
+	_, err = deployer.DeployGravityWaves(testConfig.Client, testConfig.Helper, gravityScript, consulsString, BftValue, cfg.ChainId, testConfig.Gravity.Secret, testConfig.Ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	err = deployer.DeploySubWaves(testConfig.Client, testConfig.Helper, subScript, "Nebula", "assetId", cfg.ChainId, testConfig.Sub.Secret, testConfig.Ctx)
+	_, err = deployer.DeploySubWaves(testConfig.Client, testConfig.Helper, subScript, "Nebula", "assetId", cfg.ChainId, testConfig.Sub.Secret, testConfig.Ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -204,7 +204,7 @@ func initTests() (*helper.TestPulseConfig, error) {
 	for _, v := range testConfig.Oracles {
 		oraclesString = append(oraclesString, v.PubKey.String())
 	}
-	err = deployer.DeployNebulaWaves(testConfig.Client, testConfig.Helper, nebulaScript, testConfig.Gravity.Address,
+	_, err = deployer.DeployNebulaWaves(testConfig.Client, testConfig.Helper, nebulaScript, testConfig.Gravity.Address,
 		testConfig.Sub.Address, oraclesString, BftValue, contracts.BytesType, cfg.ChainId, testConfig.Nebula.Secret, testConfig.Ctx)
 	if err != nil {
 		return nil, err